@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// weekdays maps lowercase day names to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// isoDueRe matches words shaped like an @YYYY-MM-DD due date, other words
+// starting with @, such as mentions, are part of the task
+var isoDueRe = regexp.MustCompile(`^@\d{4}-\d{2}-\d{2}$`)
+
+// parseDue looks for a due date phrase at the end of task and returns the task
+// without it, plus the due date (midnight of that day) relative to now.
+// Supported phrases:
+//
+//	today, tomorrow
+//	next week, next month, next <weekday>, on <weekday>
+//	in <n> day(s)|week(s)|month(s)
+//	@YYYY-MM-DD
+//
+// A task without a due date phrase is returned unchanged with a zero time.
+func parseDue(task string, now time.Time) (string, time.Time, error) {
+	words := strings.Fields(task)
+	if len(words) == 0 {
+		return task, time.Time{}, nil
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	last := strings.ToLower(words[len(words)-1])
+
+	var (
+		due time.Time
+		n   int // number of words making up the phrase
+	)
+
+	switch {
+	case isoDueRe.MatchString(last):
+		d, err := time.ParseInLocation("2006-01-02", last[1:], now.Location())
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("invalid due date %q: expected @YYYY-MM-DD", words[len(words)-1])
+		}
+		due, n = d, 1
+	case last == "today":
+		due, n = today, 1
+	case last == "tomorrow":
+		due, n = today.AddDate(0, 0, 1), 1
+	case len(words) >= 2:
+		prev := strings.ToLower(words[len(words)-2])
+		if wd, ok := weekdays[last]; ok && (prev == "next" || prev == "on") {
+			due, n = nextWeekday(today, wd), 2
+			break
+		}
+		if prev == "next" && last == "week" {
+			due, n = today.AddDate(0, 0, 7), 2
+			break
+		}
+		if prev == "next" && last == "month" {
+			due, n = today.AddDate(0, 1, 0), 2
+			break
+		}
+		if len(words) >= 3 && strings.ToLower(words[len(words)-3]) == "in" {
+			d, ok := parseOffset(today, prev, last)
+			if ok {
+				due, n = d, 3
+			}
+		}
+	}
+
+	if n == 0 {
+		return task, time.Time{}, nil
+	}
+
+	return strings.Join(words[:len(words)-n], " "), due, nil
+}
+
+// nextWeekday returns the first wd strictly after today
+func nextWeekday(today time.Time, wd time.Weekday) time.Time {
+	days := int(wd-today.Weekday()+7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return today.AddDate(0, 0, days)
+}
+
+// parseOffset handles the "<n> <unit>" part of "in <n> <unit>"
+func parseOffset(today time.Time, num, unit string) (time.Time, bool) {
+	n, err := strconv.Atoi(num)
+	if err != nil || n < 0 {
+		return time.Time{}, false
+	}
+
+	switch strings.TrimSuffix(unit, "s") {
+	case "day":
+		return today.AddDate(0, 0, n), true
+	case "week":
+		return today.AddDate(0, 0, 7*n), true
+	case "month":
+		return today.AddDate(0, n, 0), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDue(t *testing.T) {
+	// Wednesday, fixed clock so results are deterministic
+	now := time.Date(2026, time.October, 14, 15, 30, 0, 0, time.UTC)
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		name    string
+		task    string
+		expTask string
+		expDue  time.Time
+		expErr  bool
+	}{
+		{"NoDueDate", "buy milk", "buy milk", time.Time{}, false},
+		{"Today", "buy milk today", "buy milk", day(2026, 10, 14), false},
+		{"Tomorrow", "buy milk Tomorrow", "buy milk", day(2026, 10, 15), false},
+		{"NextWeekday", "pay rent next friday", "pay rent", day(2026, 10, 16), false},
+		{"NextSameWeekday", "team sync next wednesday", "team sync", day(2026, 10, 21), false},
+		{"OnWeekday", "call bank on monday", "call bank", day(2026, 10, 19), false},
+		{"NextWeek", "review PR next week", "review PR", day(2026, 10, 21), false},
+		{"NextMonth", "renew domain next month", "renew domain", day(2026, 11, 14), false},
+		{"InDays", "water plants in 3 days", "water plants", day(2026, 10, 17), false},
+		{"InOneDay", "water plants in 1 day", "water plants", day(2026, 10, 15), false},
+		{"InWeeks", "dentist in 2 weeks", "dentist", day(2026, 10, 28), false},
+		{"InMonths", "taxes in 2 months", "taxes", day(2026, 12, 14), false},
+		{"ISODate", "ship release @2026-12-01", "ship release", day(2026, 12, 1), false},
+		{"ISODateInvalid", "ship release @2026-13-01", "", time.Time{}, true},
+		{"Mention", "email @john", "email @john", time.Time{}, false},
+		{"MentionNotADate", "sync @2026-q4", "sync @2026-q4", time.Time{}, false},
+		{"PhraseNotTrailing", "tomorrow buy milk", "tomorrow buy milk", time.Time{}, false},
+		{"WeekdayWithoutPrefix", "thank god it's friday", "thank god it's friday", time.Time{}, false},
+		{"InUnknownUnit", "done in 5 minutes", "done in 5 minutes", time.Time{}, false},
+		{"InNotANumber", "read in a day", "read in a day", time.Time{}, false},
+		{"OnlyPhrase", "tomorrow", "", day(2026, 10, 15), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			task, due, err := parseDue(tc.task, now)
+			if tc.expErr {
+				if err == nil {
+					t.Fatal("Expected error, got nil instead")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if task != tc.expTask {
+				t.Errorf("Got task %q, want %q", task, tc.expTask)
+			}
+			if !due.Equal(tc.expDue) {
+				t.Errorf("Got due date %s, want %s", due, tc.expDue)
+			}
+		})
+	}
+}
+
+func TestGetTask(t *testing.T) {
+	now := time.Date(2026, time.October, 14, 15, 30, 0, 0, time.UTC)
+
+	t.Run("FromArgs", func(t *testing.T) {
+		task, due, err := getTask(nil, now, "pay", "rent", "tomorrow")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task != "pay rent" {
+			t.Errorf("Got %q, want %q", task, "pay rent")
+		}
+		if due.Day() != 15 {
+			t.Errorf("Got due day %d, want %d", due.Day(), 15)
+		}
+	})
+
+	t.Run("FromSTDIN", func(t *testing.T) {
+		task, due, err := getTask(strings.NewReader("pay rent @2026-11-01\n"), now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task != "pay rent" {
+			t.Errorf("Got %q, want %q", task, "pay rent")
+		}
+		if due.Month() != time.November {
+			t.Errorf("Got due month %s, want %s", due.Month(), time.November)
+		}
+	})
+
+	t.Run("BlankAfterDueDate", func(t *testing.T) {
+		if _, _, err := getTask(nil, now, "tomorrow"); err == nil {
+			t.Error("Expected error, got nil instead")
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/karanbirsingh7/pclaig/todo"
 )
@@ -26,6 +27,18 @@ func main() {
 	delete := flag.Int("delete", 0, "Item to delete from list")
 	verbose := flag.Bool("verbose", false, "Verbose output when listing tasks")
 	pending := flag.Bool("pending", false, "Show only pending items")
+	search := flag.String("search", "", "Search tasks containing text (case-insensitive)")
+	regex := flag.Bool("regex", false, "Treat search text as a regular expression")
+	keyFile := flag.String("keyfile", "", "Key file used to encrypt/decrypt the ToDo file")
+	interactive := flag.Bool("tui", false, "Browse and edit tasks interactively")
+	syncURL := flag.String("sync", "", "Sync tasks with todoServer API at this URL")
+	remindDue := flag.Bool("remind", false, "Print summary of overdue and due today tasks")
+	watchDue := flag.Bool("watch", false, "Keep running and notify when tasks become due")
+	interval := flag.Duration("interval", time.Minute, "How often to check for due tasks in watch mode")
+	start := flag.Int("start", 0, "Item to start tracking time on")
+	stop := flag.Int("stop", 0, "Item to stop tracking time on")
+	stats := flag.Bool("stats", false, "Show task and tracked time statistics")
+	notifyCmd := flag.String("notify", "", "Command to run for due task notifications in watch mode, default prints to STDOUT")
 
 	flag.Usage = func() {
 		fmt.Println("My TODO CLI")
@@ -34,6 +47,11 @@ func main() {
 
 	flag.Parse()
 
+	// key file is picked up by todo.List when saving and reading
+	if *keyFile != "" {
+		os.Setenv("TODO_KEYFILE", *keyFile)
+	}
+
 	l := &todo.List{}
 
 	// if any issues with reading file, print to STDERR and exit
@@ -54,6 +72,67 @@ func main() {
 	case *list:
 		// list flag means list all items
 		fmt.Print(l)
+	case *interactive:
+		// changes are saved by the interactive mode as they happen
+		if err := runTUI(l, todoFileName); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case *syncURL != "":
+		// reconcile with server, then save merged list
+		if err := syncTasks(os.Stdout, l, newSyncClient(*syncURL)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if err := l.Save(todoFileName); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case *remindDue:
+		if err := remind(os.Stdout, l, time.Now()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case *watchDue:
+		if err := watch(os.Stdout, todoFileName, *interval, *notifyCmd); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case *stats:
+		if err := printStats(os.Stdout, l, time.Now()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case *start > 0:
+		// start timer on given item
+		if err := l.Start(*start); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		// save new list
+		if err := l.Save(todoFileName); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case *stop > 0:
+		// stop timer on given item
+		if err := l.Stop(*stop); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		// save new list
+		if err := l.Save(todoFileName); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case *search != "":
+		// print matching items along with their item numbers
+		if err := searchTasks(os.Stdout, l, *search, *regex, isTerminal(os.Stdout)); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
 	case *delete > 0:
 		// delete the item
 		if err := l.Delete(*delete); err != nil {
@@ -80,14 +159,14 @@ func main() {
 
 	case *add:
 		// when any arguments are provided, they will be used as new task
-		t, err := getTask(os.Stdin, flag.Args()...)
+		t, due, err := getTask(os.Stdin, time.Now(), flag.Args()...)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
 
 		// add new task
-		l.Add(t)
+		l.AddDue(t, due)
 
 		// save list
 		if err := l.Save(todoFileName); err != nil {
@@ -101,23 +180,89 @@ func main() {
 	}
 }
 
-// getTask decides where to get new task from, could be STDIN or arguments
-func getTask(r io.Reader, args ...string) (string, error) {
+// getTask decides where to get new task from, could be STDIN or arguments.
+// a trailing due date phrase is parsed relative to now and removed from the task
+func getTask(r io.Reader, now time.Time, args ...string) (string, time.Time, error) {
+	task := ""
+
 	if len(args) > 0 {
-		return strings.Join(args, " "), nil
+		task = strings.Join(args, " ")
+	} else {
+		s := bufio.NewScanner(r)
+		s.Scan()
+
+		if err := s.Err(); err != nil {
+			return "", time.Time{}, err
+		}
+		task = s.Text()
+	}
+
+	task, due, err := parseDue(task, now)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if len(task) == 0 {
+		return "", time.Time{}, fmt.Errorf("task cannot be blank")
 	}
 
-	s := bufio.NewScanner(r)
-	s.Scan()
+	return task, due, nil
+}
 
-	if err := s.Err(); err != nil {
-		return "", err
+// printStats prints item counts and time tracked across the list
+func printStats(out io.Writer, l *todo.List, now time.Time) error {
+	done := 0
+	running := 0
+	for _, t := range *l {
+		if t.Done {
+			done++
+		}
+		if t.Running() {
+			running++
+		}
 	}
 
-	if len(s.Text()) == 0 {
-		return "", fmt.Errorf("task cannot be blank")
+	_, err := fmt.Fprintf(out, "Tasks: %d (%d completed, %d pending)\nTracked: %s (%d running)\n",
+		len(*l), done, len(*l)-done, l.TotalTracked(now).Round(time.Second), running)
+	return err
+}
+
+// ANSI escape codes used to highlight search matches
+const (
+	highlightStart = "\033[1;31m"
+	highlightEnd   = "\033[0m"
+)
+
+// searchTasks prints items matching expr, highlighting matches if color is set
+func searchTasks(out io.Writer, l *todo.List, expr string, useRegex, color bool) error {
+	matches, err := l.Search(expr, useRegex)
+	if err != nil {
+		return err
 	}
 
-	return s.Text(), nil
+	for _, m := range matches {
+		prefix := "  "
+		if m.Done {
+			prefix = "X "
+		}
+
+		task := m.Task
+		if color {
+			task = m.Highlight(highlightStart, highlightEnd)
+		}
+
+		if _, err := fmt.Fprintf(out, "%s%d: %s\n", prefix, m.Num, task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+// isTerminal checks if f is an interactive terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
 }
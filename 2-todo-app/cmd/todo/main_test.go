@@ -62,6 +62,30 @@ func TestTODOCLI(t *testing.T) {
 		}
 	})
 
+	t.Run("SearchTasks", func(t *testing.T) {
+		cmd := exec.Command(cmdPath, "-search", "NUMBER 2")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := fmt.Sprintf("  2: %s\n", task2)
+		if string(out) != expected {
+			t.Errorf("Got %q, want %q instead\n", string(out), expected)
+		}
+	})
+
+	t.Run("SearchTasksRegex", func(t *testing.T) {
+		cmd := exec.Command(cmdPath, "-search", "number [0-9]$", "-regex")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := fmt.Sprintf("  1: %s\n  2: %s\n", task, task2)
+		if string(out) != expected {
+			t.Errorf("Got %q, want %q instead\n", string(out), expected)
+		}
+	})
+
 	t.Run("CompleteTask", func(t *testing.T) {
 		cmd := exec.Command(cmdPath, "-complete", "1")
 		if err := cmd.Run(); err != nil {
@@ -69,6 +93,36 @@ func TestTODOCLI(t *testing.T) {
 		}
 	})
 
+	t.Run("TrackTime", func(t *testing.T) {
+		for _, args := range [][]string{{"-start", "2"}, {"-stop", "2"}} {
+			cmd := exec.Command(cmdPath, args...)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("%v: %s", err, out)
+			}
+		}
+
+		cmd := exec.Command(cmdPath, "-list", "-verbose")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(out), "Tracked:") {
+			t.Errorf("Expected tracked time in %q", string(out))
+		}
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		cmd := exec.Command(cmdPath, "-stats")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := "Tasks: 2 (1 completed, 1 pending)\n"
+		if !strings.HasPrefix(string(out), expected) {
+			t.Errorf("Got %q, want prefix %q instead\n", string(out), expected)
+		}
+	})
+
 	t.Run("ListTasksPendingOnly", func(t *testing.T) {
 		cmd := exec.Command(cmdPath, "-list", "-pending")
 		out, err := cmd.CombinedOutput()
@@ -94,6 +148,17 @@ func TestTODOCLI(t *testing.T) {
 		}
 	})
 
+	t.Run("RemindNothingDue", func(t *testing.T) {
+		cmd := exec.Command(cmdPath, "-remind")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != "" {
+			t.Errorf("Expected no output, got %q instead\n", string(out))
+		}
+	})
+
 	t.Run("DeleteTask", func(t *testing.T) {
 		itemToDelete := "1"
 		cmd := exec.Command(cmdPath, "-delete", itemToDelete)
@@ -104,6 +169,11 @@ func TestTODOCLI(t *testing.T) {
 }
 
 func TestMain(m *testing.M) {
+	// helper processes mocking external commands don't need the tool built
+	if os.Getenv("GO_WANT_HELPER_PROCESS") == "1" {
+		os.Exit(m.Run())
+	}
+
 	fmt.Println("Building tool")
 	fmt.Println("Setting environment variable TODO_FILENAME=", fileName)
 
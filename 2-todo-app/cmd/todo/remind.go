@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/karanbirsingh7/pclaig/todo"
+)
+
+// command is a package variable so tests can mock notification commands
+var command = exec.Command
+
+// remind prints a one line summary of overdue and due today tasks.
+// nothing is printed when no task is due, to keep shell prompts clean
+func remind(out io.Writer, l *todo.List, now time.Time) error {
+	overdue := len(l.Overdue(now))
+	today := len(l.DueToday(now))
+
+	if overdue == 0 && today == 0 {
+		return nil
+	}
+
+	parts := []string{}
+	if overdue > 0 {
+		parts = append(parts, fmt.Sprintf("%d overdue", overdue))
+	}
+	if today > 0 {
+		parts = append(parts, fmt.Sprintf("%d due today", today))
+	}
+
+	_, err := fmt.Fprintf(out, "todo: %s\n", strings.Join(parts, ", "))
+	return err
+}
+
+// checkDue returns messages for pending tasks that are due and were not
+// notified yet, marking them as notified
+func checkDue(l *todo.List, now time.Time, notified map[string]bool) []string {
+	nums := append(l.Overdue(now), l.DueToday(now)...)
+	msgs := []string{}
+
+	for _, n := range nums {
+		it := (*l)[n-1]
+
+		// task + due date identifies an item even if its number changes
+		key := it.Task + "@" + it.DueDate.Format("2006-01-02")
+		if notified[key] {
+			continue
+		}
+		notified[key] = true
+
+		msgs = append(msgs, fmt.Sprintf("task due: %d: %s (due %s)", n, it.Task, it.DueDate.Format("2006-01-02")))
+	}
+	return msgs
+}
+
+// notify prints msg, or runs notifyCmd with msg as its last argument when set
+func notify(out io.Writer, notifyCmd, msg string) error {
+	if notifyCmd == "" {
+		_, err := fmt.Fprintln(out, msg)
+		return err
+	}
+
+	args := strings.Fields(notifyCmd)
+	args = append(args, msg)
+
+	cmd := command(args[0], args[1:]...)
+	cmd.Stdout = out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("notify command %q failed: %w", notifyCmd, err)
+	}
+	return nil
+}
+
+// watch reloads the todo file every interval and notifies when tasks become
+// due, until it receives an interrupt signal
+func watch(out io.Writer, filename string, interval time.Duration, notifyCmd string) error {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sig)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	notified := map[string]bool{}
+
+	for {
+		// file is read on every check to pick up changes from other commands
+		l := &todo.List{}
+		if err := l.Get(filename); err != nil {
+			return err
+		}
+
+		for _, msg := range checkDue(l, time.Now(), notified) {
+			if err := notify(out, notifyCmd, msg); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-sig:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
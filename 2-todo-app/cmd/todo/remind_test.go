@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/karanbirsingh7/pclaig/todo"
+)
+
+// mockCommand - runs TestHelperProcess instead of the real command
+func mockCommand(exe string, args ...string) *exec.Cmd {
+	cs := []string{"-test.run=TestHelperProcess", "--", exe}
+	cs = append(cs, args...)
+
+	cmd := exec.Command(os.Args[0], cs...)
+	cmd.Env = []string{"GO_WANT_HELPER_PROCESS=1"}
+	return cmd
+}
+
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	// echo back the notification command and its arguments
+	fmt.Fprintln(os.Stdout, os.Args[3:])
+	os.Exit(0)
+}
+
+// dueList - list with tasks due at different days around now
+func dueList(now time.Time) *todo.List {
+	l := &todo.List{}
+	l.AddDue("pay rent", now.AddDate(0, 0, -2))
+	l.AddDue("call bank", now)
+	l.AddDue("dentist", now.AddDate(0, 0, 1))
+	return l
+}
+
+func TestRemind(t *testing.T) {
+	now := time.Date(2026, time.October, 14, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		list     *todo.List
+		expected string
+	}{
+		{"OverdueAndToday", dueList(now), "todo: 1 overdue, 1 due today\n"},
+		{"Nothing", &todo.List{}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := remind(&out, tc.list, now); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tc.expected {
+				t.Errorf("Got %q, want %q", out.String(), tc.expected)
+			}
+		})
+	}
+}
+
+func TestCheckDue(t *testing.T) {
+	now := time.Date(2026, time.October, 14, 9, 0, 0, 0, time.UTC)
+	l := dueList(now)
+	notified := map[string]bool{}
+
+	msgs := checkDue(l, now, notified)
+	expected := []string{
+		"task due: 1: pay rent (due 2026-10-12)",
+		"task due: 2: call bank (due 2026-10-14)",
+	}
+	if fmt.Sprint(msgs) != fmt.Sprint(expected) {
+		t.Errorf("Got %q, want %q", msgs, expected)
+	}
+
+	// already notified tasks are skipped
+	if msgs := checkDue(l, now, notified); len(msgs) != 0 {
+		t.Errorf("Expected no messages, got %q", msgs)
+	}
+
+	// next day the dentist task becomes due
+	msgs = checkDue(l, now.AddDate(0, 0, 1), notified)
+	expected = []string{"task due: 3: dentist (due 2026-10-15)"}
+	if fmt.Sprint(msgs) != fmt.Sprint(expected) {
+		t.Errorf("Got %q, want %q", msgs, expected)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	testCases := []struct {
+		name      string
+		notifyCmd string
+		expected  string
+	}{
+		{"Stdout", "", "task due: 1: pay rent\n"},
+		{"Command", "notify-send -u critical", "[notify-send -u critical task due: 1: pay rent]\n"},
+	}
+
+	command = mockCommand
+	defer func() { command = exec.Command }()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := notify(&out, tc.notifyCmd, "task due: 1: pay rent"); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tc.expected {
+				t.Errorf("Got %q, want %q", out.String(), tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/karanbirsingh7/pclaig/todo"
+)
+
+// syncClient talks to the todoServer API
+type syncClient struct {
+	apiRoot string
+	client  *http.Client
+}
+
+// newSyncClient - constructor for syncClient, apiRoot like http://localhost:8080
+func newSyncClient(apiRoot string) *syncClient {
+	return &syncClient{
+		apiRoot: strings.TrimSuffix(apiRoot, "/") + "/todo",
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// syncResult counts changes made by a sync
+type syncResult struct {
+	pushed        int
+	pulled        int
+	updatedLocal  int
+	updatedRemote int
+}
+
+// getAll fetches the full remote list
+func (c *syncClient) getAll() (todo.List, error) {
+	r, err := c.client.Get(c.apiRoot)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get remote list: %w", err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cannot get remote list: %s", r.Status)
+	}
+
+	resp := struct {
+		Results todo.List `json:"results"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("invalid response from server: %w", err)
+	}
+	return resp.Results, nil
+}
+
+// add creates a new task on the server
+func (c *syncClient) add(task string) error {
+	var body bytes.Buffer
+	item := struct {
+		Task string `json:"task"`
+	}{
+		Task: task,
+	}
+	if err := json.NewEncoder(&body).Encode(item); err != nil {
+		return err
+	}
+
+	r, err := c.client.Post(c.apiRoot, "application/json", &body)
+	if err != nil {
+		return fmt.Errorf("cannot add task %q: %w", task, err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusCreated {
+		return fmt.Errorf("cannot add task %q: %s", task, r.Status)
+	}
+	return nil
+}
+
+// setDone completes or reopens remote item id
+func (c *syncClient) setDone(id int, done bool) error {
+	param := "uncomplete"
+	if done {
+		param = "complete"
+	}
+
+	req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("%s/%d?%s", c.apiRoot, id, param), nil)
+	if err != nil {
+		return err
+	}
+
+	r, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot update item %d: %w", id, err)
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("cannot update item %d: %s", id, r.Status)
+	}
+	return nil
+}
+
+// syncTasks reconciles local list with the server.
+// items are matched by task text: unmatched local items are pushed, unmatched
+// remote items are pulled, and when completion status differs the most
+// recently modified side wins. Deletions are not propagated.
+// Remote completion status is updated in a second pass, against a fresh list,
+// as IDs change when items are added or deleted by other clients meanwhile
+func syncTasks(out io.Writer, l *todo.List, c *syncClient) error {
+	remote, err := c.getAll()
+	if err != nil {
+		return err
+	}
+
+	res := syncResult{}
+	matched := make([]bool, len(remote))
+
+	// local items whose completion status must be set on the server
+	pushDone := map[int]bool{}
+	n := len(*l)
+
+	for i := range *l {
+		local := &(*l)[i]
+
+		j := findTask(remote, matched, local.Task)
+		if j < 0 {
+			if err := c.add(local.Task); err != nil {
+				return err
+			}
+			if local.Done {
+				pushDone[i] = true
+			}
+			res.pushed++
+			continue
+		}
+
+		matched[j] = true
+		r := remote[j]
+
+		if local.Done == r.Done {
+			continue
+		}
+
+		if r.LastModified().After(local.LastModified()) {
+			local.Done = r.Done
+			local.CompletedAt = r.CompletedAt
+			local.ModifiedAt = r.LastModified()
+			res.updatedLocal++
+			continue
+		}
+
+		pushDone[i] = true
+		res.updatedRemote++
+	}
+
+	for j, r := range remote {
+		if !matched[j] {
+			*l = append(*l, r)
+			res.pulled++
+		}
+	}
+
+	if len(pushDone) > 0 {
+		if err := pushStatus(*l, n, pushDone, c); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(out, "Sync: %d pushed, %d pulled, %d updated locally, %d updated remotely\n",
+		res.pushed, res.pulled, res.updatedLocal, res.updatedRemote)
+	return err
+}
+
+// pushStatus sets completion status of local items in pushDone on the server.
+// the first n local items are matched again with a fresh remote list, in the
+// same order as the first pass, so each one finds its current remote ID
+func pushStatus(l todo.List, n int, pushDone map[int]bool, c *syncClient) error {
+	remote, err := c.getAll()
+	if err != nil {
+		return err
+	}
+
+	matched := make([]bool, len(remote))
+	for i, local := range l[:n] {
+		j := findTask(remote, matched, local.Task)
+		if j < 0 {
+			if pushDone[i] {
+				return fmt.Errorf("cannot update task %q: not found on server", local.Task)
+			}
+			continue
+		}
+		matched[j] = true
+
+		if pushDone[i] && remote[j].Done != local.Done {
+			if err := c.setDone(j+1, local.Done); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// findTask returns index of the first unmatched item with same task, -1 if none
+func findTask(l todo.List, matched []bool, task string) int {
+	for j, it := range l {
+		if !matched[j] && it.Task == task {
+			return j
+		}
+	}
+	return -1
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/karanbirsingh7/pclaig/todo"
+)
+
+// setupSyncAPI - test server that mimics the todoServer /todo endpoint,
+// onAdd if set runs before each new item is added, like another client would
+func setupSyncAPI(t *testing.T, remote *todo.List, onAdd func()) (string, func()) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/todo", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(struct {
+				Results todo.List `json:"results"`
+			}{*remote})
+		case http.MethodPost:
+			item := struct {
+				Task string `json:"task"`
+			}{}
+			if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if onAdd != nil {
+				onAdd()
+			}
+			remote.Add(item.Task)
+			w.WriteHeader(http.StatusCreated)
+		}
+	})
+	mux.HandleFunc("/todo/", func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/todo/"))
+		if err != nil || r.Method != http.MethodPatch {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		q := r.URL.Query()
+		if _, ok := q["complete"]; ok {
+			err = remote.Complete(id)
+		} else {
+			err = remote.Uncomplete(id)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	ts := httptest.NewServer(mux)
+	return ts.URL, ts.Close
+}
+
+func TestSyncTasks(t *testing.T) {
+	old := time.Now().Add(-time.Hour)
+
+	remote := &todo.List{}
+	remote.Add("shared task")
+	remote.Add("remote only")
+	remote.Add("shared task 2")
+	remote.Complete(1)
+	(*remote)[2].ModifiedAt = old
+
+	local := &todo.List{}
+	local.Add("shared task")
+	local.Add("local only")
+	local.Add("shared task 2")
+	local.Complete(2)
+	local.Complete(3)
+	(*local)[0].ModifiedAt = old
+
+	url, cleanup := setupSyncAPI(t, remote, nil)
+	defer cleanup()
+
+	var out bytes.Buffer
+	if err := syncTasks(&out, local, newSyncClient(url+"/")); err != nil {
+		t.Fatal(err)
+	}
+
+	expOut := "Sync: 1 pushed, 1 pulled, 1 updated locally, 1 updated remotely\n"
+	if out.String() != expOut {
+		t.Errorf("Got %q, want %q", out.String(), expOut)
+	}
+
+	t.Run("Local", func(t *testing.T) {
+		expected := []struct {
+			task string
+			done bool
+		}{
+			{"shared task", true},
+			{"local only", true},
+			{"shared task 2", true},
+			{"remote only", false},
+		}
+		checkList(t, *local, expected)
+	})
+
+	t.Run("Remote", func(t *testing.T) {
+		expected := []struct {
+			task string
+			done bool
+		}{
+			{"shared task", true},
+			{"remote only", false},
+			{"shared task 2", true},
+			{"local only", true},
+		}
+		checkList(t, *remote, expected)
+	})
+
+	t.Run("SecondSyncNoChanges", func(t *testing.T) {
+		out.Reset()
+		if err := syncTasks(&out, local, newSyncClient(url)); err != nil {
+			t.Fatal(err)
+		}
+		expOut := "Sync: 0 pushed, 0 pulled, 0 updated locally, 0 updated remotely\n"
+		if out.String() != expOut {
+			t.Errorf("Got %q, want %q", out.String(), expOut)
+		}
+	})
+}
+
+func TestSyncTasksConcurrentChange(t *testing.T) {
+	// another client changes the server while new items are pushed, so IDs
+	// seen at the start of the sync are stale
+	testCases := []struct {
+		name     string
+		change   func(remote *todo.List)
+		expected []struct {
+			task string
+			done bool
+		}
+	}{
+		{
+			name:   "ItemAdded",
+			change: func(remote *todo.List) { remote.Add("other client") },
+			expected: []struct {
+				task string
+				done bool
+			}{{"first", false}, {"second", true}, {"other client", false}, {"new task", true}},
+		},
+		{
+			name: "ItemDeleted",
+			change: func(remote *todo.List) {
+				remote.Delete(1)
+				remote.Add("other client")
+			},
+			expected: []struct {
+				task string
+				done bool
+			}{{"second", true}, {"other client", false}, {"new task", true}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			old := time.Now().Add(-time.Hour)
+
+			remote := &todo.List{}
+			remote.Add("first")
+			remote.Add("second")
+			(*remote)[1].ModifiedAt = old
+
+			local := &todo.List{}
+			local.Add("new task")
+			local.Add("second")
+			local.Complete(1)
+			local.Complete(2)
+
+			changed := false
+			url, cleanup := setupSyncAPI(t, remote, func() {
+				if !changed {
+					tc.change(remote)
+					changed = true
+				}
+			})
+			defer cleanup()
+
+			var out bytes.Buffer
+			if err := syncTasks(&out, local, newSyncClient(url)); err != nil {
+				t.Fatal(err)
+			}
+
+			checkList(t, *remote, tc.expected)
+		})
+	}
+}
+
+func TestSyncTasksServerError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	var out bytes.Buffer
+	if err := syncTasks(&out, &todo.List{}, newSyncClient(ts.URL)); err == nil {
+		t.Error("Expected error, got nil instead")
+	}
+}
+
+// checkList - compares tasks and their status in order
+func checkList(t *testing.T, l todo.List, expected []struct {
+	task string
+	done bool
+}) {
+	t.Helper()
+
+	if len(l) != len(expected) {
+		t.Fatalf("Expected %d items, got %d instead", len(expected), len(l))
+	}
+	for i, exp := range expected {
+		if l[i].Task != exp.task || l[i].Done != exp.done {
+			t.Errorf("Item %d: got (%q, %t), want (%q, %t)", i+1, l[i].Task, l[i].Done, exp.task, exp.done)
+		}
+	}
+}
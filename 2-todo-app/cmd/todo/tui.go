@@ -0,0 +1,396 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/karanbirsingh7/pclaig/todo"
+	"golang.org/x/term"
+)
+
+// tui modes, decide how key presses are handled
+const (
+	modeBrowse = iota
+	modeAdd
+	modeFilter
+	modeConfirmDelete
+)
+
+// special keys returned by readKey, any other key is returned as typed
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyEnter     = "enter"
+	keyEsc       = "esc"
+	keyBackspace = "backspace"
+	keyCtrlC     = "ctrl+c"
+)
+
+// tui holds the state of the interactive mode, without any terminal I/O
+type tui struct {
+	list *todo.List
+
+	// save persists the list after every change
+	save func(*todo.List) error
+
+	// clock used to parse due dates of new tasks
+	now func() time.Time
+
+	mode   int
+	cursor int // index into visible items
+	offset int // first visible item shown on screen
+	height int // number of items that fit on screen
+
+	filter  string
+	input   string
+	message string
+}
+
+// newTUI - constructor for tui, sets defaults
+func newTUI(l *todo.List, save func(*todo.List) error) *tui {
+	return &tui{
+		list:   l,
+		save:   save,
+		now:    time.Now,
+		height: 20,
+	}
+}
+
+// visible returns the item numbers shown with the current filter
+func (t *tui) visible() []int {
+	nums := []int{}
+
+	if t.filter == "" {
+		for i := range *t.list {
+			nums = append(nums, i+1)
+		}
+		return nums
+	}
+
+	matches, err := t.list.Search(t.filter, false)
+	if err != nil {
+		return nums
+	}
+	for _, m := range matches {
+		nums = append(nums, m.Num)
+	}
+	return nums
+}
+
+// selected returns the item number under the cursor, 0 if none
+func (t *tui) selected() int {
+	nums := t.visible()
+	if t.cursor < 0 || t.cursor >= len(nums) {
+		return 0
+	}
+	return nums[t.cursor]
+}
+
+// moveCursor moves the cursor by delta, keeping it inside the visible items
+func (t *tui) moveCursor(delta int) {
+	n := len(t.visible())
+
+	t.cursor += delta
+	if t.cursor >= n {
+		t.cursor = n - 1
+	}
+	if t.cursor < 0 {
+		t.cursor = 0
+	}
+
+	// scroll so cursor is always on screen
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	}
+	if t.height > 0 && t.cursor >= t.offset+t.height {
+		t.offset = t.cursor - t.height + 1
+	}
+}
+
+// handleKey updates state according to key, returns true when user quits
+func (t *tui) handleKey(key string) (bool, error) {
+	if key == keyCtrlC {
+		return true, nil
+	}
+
+	switch t.mode {
+	case modeAdd:
+		return false, t.handleAdd(key)
+	case modeFilter:
+		t.handleFilter(key)
+		return false, nil
+	case modeConfirmDelete:
+		return false, t.handleConfirmDelete(key)
+	}
+
+	t.message = ""
+
+	switch key {
+	case "q", keyEsc:
+		return true, nil
+	case keyUp, "k":
+		t.moveCursor(-1)
+	case keyDown, "j":
+		t.moveCursor(1)
+	case " ", "x":
+		return false, t.toggle()
+	case "d":
+		if t.selected() > 0 {
+			t.mode = modeConfirmDelete
+		}
+	case "a":
+		t.mode = modeAdd
+		t.input = ""
+	case "/":
+		t.mode = modeFilter
+	}
+	return false, nil
+}
+
+// toggle flips the completed status of the selected item
+func (t *tui) toggle() error {
+	i := t.selected()
+	if i == 0 {
+		return nil
+	}
+
+	if (*t.list)[i-1].Done {
+		if err := t.list.Uncomplete(i); err != nil {
+			return err
+		}
+	} else if err := t.list.Complete(i); err != nil {
+		return err
+	}
+	return t.save(t.list)
+}
+
+// handleAdd edits the new task line, adding it on enter
+func (t *tui) handleAdd(key string) error {
+	switch key {
+	case keyEsc:
+		t.mode = modeBrowse
+		t.input = ""
+	case keyBackspace:
+		t.input = dropLastRune(t.input)
+	case keyEnter:
+		t.mode = modeBrowse
+
+		task, due, err := parseDue(strings.TrimSpace(t.input), t.now())
+		t.input = ""
+		if err != nil {
+			t.message = err.Error()
+			return nil
+		}
+		if task == "" {
+			t.message = "task cannot be blank"
+			return nil
+		}
+
+		t.list.AddDue(task, due)
+		t.message = fmt.Sprintf("added item %d", len(*t.list))
+		return t.save(t.list)
+	default:
+		if !isSpecialKey(key) {
+			t.input += key
+		}
+	}
+	return nil
+}
+
+// handleFilter edits the filter, list is filtered as the user types
+func (t *tui) handleFilter(key string) {
+	switch key {
+	case keyEsc:
+		t.mode = modeBrowse
+		t.filter = ""
+	case keyEnter:
+		t.mode = modeBrowse
+	case keyBackspace:
+		t.filter = dropLastRune(t.filter)
+	default:
+		if !isSpecialKey(key) {
+			t.filter += key
+		}
+	}
+
+	// filtered list may be shorter, keep cursor in range
+	t.cursor, t.offset = 0, 0
+}
+
+// handleConfirmDelete deletes selected item only when user answers yes
+func (t *tui) handleConfirmDelete(key string) error {
+	t.mode = modeBrowse
+
+	if key != "y" && key != "Y" {
+		t.message = "delete cancelled"
+		return nil
+	}
+
+	i := t.selected()
+	if err := t.list.Delete(i); err != nil {
+		return err
+	}
+	t.message = fmt.Sprintf("deleted item %d", i)
+	t.moveCursor(0)
+	return t.save(t.list)
+}
+
+// view renders current state as text, one line per row
+func (t *tui) view() string {
+	var b strings.Builder
+
+	b.WriteString("ToDo list")
+	if t.filter != "" {
+		fmt.Fprintf(&b, " (filter: %s)", t.filter)
+	}
+	b.WriteString("\n\n")
+
+	nums := t.visible()
+	if len(nums) == 0 {
+		b.WriteString("  no items\n")
+	}
+
+	end := len(nums)
+	if t.height > 0 && t.offset+t.height < end {
+		end = t.offset + t.height
+	}
+
+	for i := t.offset; i < end; i++ {
+		it := (*t.list)[nums[i]-1]
+
+		cursor := "  "
+		if i == t.cursor {
+			cursor = "> "
+		}
+		done := "[ ]"
+		if it.Done {
+			done = "[x]"
+		}
+
+		fmt.Fprintf(&b, "%s%s %d: %s", cursor, done, nums[i], it.Task)
+		if !it.DueDate.IsZero() {
+			fmt.Fprintf(&b, " (due %s)", it.DueDate.Format("2006-01-02"))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	switch t.mode {
+	case modeAdd:
+		fmt.Fprintf(&b, "New task: %s\n", t.input)
+	case modeFilter:
+		fmt.Fprintf(&b, "Filter: %s\n", t.filter)
+	case modeConfirmDelete:
+		fmt.Fprintf(&b, "Delete item %d? (y/n)\n", t.selected())
+	default:
+		fmt.Fprintf(&b, "%s\n", t.message)
+	}
+
+	b.WriteString("up/k down/j: move  space: toggle  a: add  d: delete  /: filter  q: quit\n")
+	return b.String()
+}
+
+// readKey reads a single key press from the terminal
+func readKey(r *bufio.Reader) (string, error) {
+	ch, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+
+	switch ch {
+	case 3:
+		return keyCtrlC, nil
+	case '\r', '\n':
+		return keyEnter, nil
+	case 127, '\b':
+		return keyBackspace, nil
+	case 27:
+		// a lone ESC has nothing buffered after it, arrows send ESC [ A/B
+		if r.Buffered() == 0 {
+			return keyEsc, nil
+		}
+		seq := make([]byte, 2)
+		if _, err := io.ReadFull(r, seq); err != nil {
+			return "", err
+		}
+		switch string(seq) {
+		case "[A":
+			return keyUp, nil
+		case "[B":
+			return keyDown, nil
+		}
+		return "", nil
+	}
+
+	return string(ch), nil
+}
+
+// isSpecialKey checks if key is one of the named keys rather than text
+func isSpecialKey(key string) bool {
+	switch key {
+	case keyUp, keyDown, keyEnter, keyEsc, keyBackspace, keyCtrlC, "":
+		return true
+	}
+	return false
+}
+
+// dropLastRune removes the last character of s
+func dropLastRune(s string) string {
+	r := []rune(s)
+	if len(r) == 0 {
+		return s
+	}
+	return string(r[:len(r)-1])
+}
+
+// runTUI runs the interactive mode on the terminal until the user quits
+func runTUI(l *todo.List, filename string) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("interactive mode requires a terminal")
+	}
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	// restore terminal on exit
+	defer term.Restore(fd, oldState)
+
+	t := newTUI(l, func(l *todo.List) error {
+		return l.Save(filename)
+	})
+
+	r := bufio.NewReader(os.Stdin)
+	for {
+		// leave room for header and footer lines
+		if _, h, err := term.GetSize(fd); err == nil && h > 6 {
+			t.height = h - 6
+			t.moveCursor(0)
+		}
+
+		// clear screen, raw mode requires explicit carriage returns
+		screen := strings.ReplaceAll(t.view(), "\n", "\r\n")
+		if _, err := fmt.Fprint(os.Stdout, "\033[H\033[2J"+screen); err != nil {
+			return err
+		}
+
+		key, err := readKey(r)
+		if err != nil {
+			return err
+		}
+
+		quit, err := t.handleKey(key)
+		if err != nil {
+			return err
+		}
+		if quit {
+			fmt.Fprint(os.Stdout, "\033[H\033[2J")
+			return nil
+		}
+	}
+}
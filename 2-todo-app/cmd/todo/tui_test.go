@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/karanbirsingh7/pclaig/todo"
+)
+
+// setupTUI - creates a tui over a list of tasks, counting saves
+func setupTUI(t *testing.T, tasks ...string) (*tui, *int) {
+	t.Helper()
+
+	l := &todo.List{}
+	for _, task := range tasks {
+		l.Add(task)
+	}
+
+	saves := 0
+	ui := newTUI(l, func(*todo.List) error {
+		saves++
+		return nil
+	})
+	ui.now = func() time.Time {
+		return time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC)
+	}
+	return ui, &saves
+}
+
+// pressKeys - sends keys to tui, failing on errors
+func pressKeys(t *testing.T, ui *tui, keys ...string) bool {
+	t.Helper()
+
+	quit := false
+	for _, k := range keys {
+		var err error
+		if quit, err = ui.handleKey(k); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return quit
+}
+
+func TestTUIView(t *testing.T) {
+	ui, _ := setupTUI(t, "task 1", "task 2")
+	pressKeys(t, ui, keyDown, " ")
+
+	expected := "ToDo list\n\n" +
+		"  [ ] 1: task 1\n" +
+		"> [x] 2: task 2\n\n\n" +
+		"up/k down/j: move  space: toggle  a: add  d: delete  /: filter  q: quit\n"
+
+	if got := ui.view(); got != expected {
+		t.Errorf("Got %q, want %q", got, expected)
+	}
+}
+
+func TestTUINavigation(t *testing.T) {
+	ui, _ := setupTUI(t, "task 1", "task 2", "task 3")
+
+	testCases := []struct {
+		name   string
+		keys   []string
+		expSel int
+	}{
+		{"Start", nil, 1},
+		{"Down", []string{keyDown}, 2},
+		{"DownVim", []string{"j", "j"}, 3},
+		{"PastEnd", []string{"j", "j", "j", "j"}, 3},
+		{"UpPastStart", []string{"j", "k", keyUp, keyUp}, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui.cursor, ui.offset = 0, 0
+			pressKeys(t, ui, tc.keys...)
+			if got := ui.selected(); got != tc.expSel {
+				t.Errorf("Got item %d selected, want %d", got, tc.expSel)
+			}
+		})
+	}
+}
+
+func TestTUIScroll(t *testing.T) {
+	ui, _ := setupTUI(t, "task 1", "task 2", "task 3", "task 4")
+	ui.height = 2
+
+	pressKeys(t, ui, "j", "j")
+	view := ui.view()
+
+	if strings.Contains(view, "task 1") || !strings.Contains(view, "> [ ] 3: task 3") {
+		t.Errorf("Expected view scrolled down to item 3, got %q", view)
+	}
+}
+
+func TestTUIToggle(t *testing.T) {
+	ui, saves := setupTUI(t, "task 1")
+
+	pressKeys(t, ui, " ")
+	if !(*ui.list)[0].Done {
+		t.Errorf("Expected item to be completed")
+	}
+
+	pressKeys(t, ui, "x")
+	if (*ui.list)[0].Done {
+		t.Errorf("Expected item to be pending")
+	}
+
+	if *saves != 2 {
+		t.Errorf("Expected %d saves, got %d instead", 2, *saves)
+	}
+}
+
+func TestTUIDelete(t *testing.T) {
+	testCases := []struct {
+		name     string
+		keys     []string
+		expItems int
+		expSaves int
+		expSel   int
+	}{
+		{"Confirmed", []string{"j", "d", "y"}, 1, 1, 1},
+		{"Cancelled", []string{"j", "d", "n"}, 2, 0, 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui, saves := setupTUI(t, "task 1", "task 2")
+			pressKeys(t, ui, tc.keys...)
+
+			if len(*ui.list) != tc.expItems {
+				t.Errorf("Expected %d items, got %d instead", tc.expItems, len(*ui.list))
+			}
+			if *saves != tc.expSaves {
+				t.Errorf("Expected %d saves, got %d instead", tc.expSaves, *saves)
+			}
+			if ui.selected() != tc.expSel {
+				t.Errorf("Expected cursor on item %d, got %d", tc.expSel, ui.selected())
+			}
+		})
+	}
+}
+
+func TestTUIConfirmPrompt(t *testing.T) {
+	ui, _ := setupTUI(t, "task 1", "task 2")
+	pressKeys(t, ui, "j", "d")
+
+	if !strings.Contains(ui.view(), "Delete item 2? (y/n)") {
+		t.Errorf("Expected delete confirmation, got %q", ui.view())
+	}
+}
+
+func TestTUIAdd(t *testing.T) {
+	ui, saves := setupTUI(t, "task 1")
+
+	keys := []string{"a"}
+	for _, c := range "new taskxx" {
+		keys = append(keys, string(c))
+	}
+	// fix typo before adding due date
+	keys = append(keys, keyBackspace)
+	for _, c := range " tomorrow" {
+		keys = append(keys, string(c))
+	}
+	pressKeys(t, ui, keys...)
+
+	if !strings.Contains(ui.view(), "New task: new taskx tomorrow") {
+		t.Errorf("Expected input line, got %q", ui.view())
+	}
+
+	pressKeys(t, ui, keyEnter)
+
+	if len(*ui.list) != 2 {
+		t.Fatalf("Expected %d items, got %d instead", 2, len(*ui.list))
+	}
+	it := (*ui.list)[1]
+	if it.Task != "new taskx" {
+		t.Errorf("Got %q, want %q", it.Task, "new taskx")
+	}
+	if it.DueDate.Day() != 15 {
+		t.Errorf("Got due day %d, want %d", it.DueDate.Day(), 15)
+	}
+	if *saves != 1 {
+		t.Errorf("Expected %d saves, got %d instead", 1, *saves)
+	}
+}
+
+func TestTUIAddCancel(t *testing.T) {
+	ui, saves := setupTUI(t, "task 1")
+	pressKeys(t, ui, "a", "n", "e", keyBackspace, keyEsc)
+
+	if len(*ui.list) != 1 || *saves != 0 {
+		t.Errorf("Expected no item added")
+	}
+	if ui.mode != modeBrowse {
+		t.Errorf("Expected browse mode after cancelling")
+	}
+}
+
+func TestTUIFilter(t *testing.T) {
+	ui, _ := setupTUI(t, "buy milk", "call bank", "buy bread")
+
+	pressKeys(t, ui, "/", "b", "u", "y")
+	view := ui.view()
+	if strings.Contains(view, "call bank") {
+		t.Errorf("Expected filtered out item, got %q", view)
+	}
+	if !strings.Contains(view, "(filter: buy)") {
+		t.Errorf("Expected filter in header, got %q", view)
+	}
+
+	// keep filter and select second match, which is item 3
+	pressKeys(t, ui, keyEnter, "j")
+	if ui.selected() != 3 {
+		t.Errorf("Got item %d selected, want %d", ui.selected(), 3)
+	}
+
+	// clear filter
+	pressKeys(t, ui, "/", keyEsc)
+	if len(ui.visible()) != 3 {
+		t.Errorf("Expected %d visible items, got %d", 3, len(ui.visible()))
+	}
+}
+
+func TestTUIQuit(t *testing.T) {
+	for _, k := range []string{"q", keyEsc, keyCtrlC} {
+		ui, _ := setupTUI(t, "task 1")
+		if !pressKeys(t, ui, k) {
+			t.Errorf("Expected key %q to quit", k)
+		}
+	}
+
+	// q is plain text while adding a task
+	ui, _ := setupTUI(t, "task 1")
+	if pressKeys(t, ui, "a", "q") {
+		t.Errorf("Expected q to be typed, not quit")
+	}
+}
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("\x1b[A\x1b[Bj\r\x7fé\x03"))
+	expected := []string{keyUp, keyDown, "j", keyEnter, keyBackspace, "é", keyCtrlC}
+
+	for _, exp := range expected {
+		got, err := readKey(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != exp {
+			t.Errorf("Got key %q, want %q", got, exp)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package todo
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// encHeader marks the start of an encrypted todo file
+var encHeader = []byte("TODOENC1")
+
+var (
+	ErrNoKey    = errors.New("file is encrypted: set TODO_KEYFILE to decrypt it")
+	ErrWrongKey = errors.New("unable to decrypt file: wrong key or corrupted data")
+)
+
+// readKey loads the key file set in TODO_KEYFILE.
+// it returns a nil key when encryption is not enabled.
+func readKey() ([]byte, error) {
+	keyFile := os.Getenv("TODO_KEYFILE")
+	if keyFile == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read key file: %w", err)
+	}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("key file %s is empty", keyFile)
+	}
+
+	// hash key file contents so any file size gives us a valid AES-256 key
+	key := sha256.Sum256(data)
+	return key[:], nil
+}
+
+// newGCM creates AES-GCM cipher from key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encrypt seals data with key, output: header + nonce + ciphertext
+func encrypt(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	out := append([]byte{}, encHeader...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, data, encHeader), nil
+}
+
+// decrypt opens data previously sealed by encrypt
+func decrypt(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data = data[len(encHeader):]
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrWrongKey
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ciphertext, encHeader)
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+	return plain, nil
+}
+
+// isEncrypted checks if file contents were written by encrypt
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encHeader)
+}
@@ -1,3 +1,7 @@
 module github.com/karanbirsingh7/pclaig/todo
 
 go 1.19
+
+require golang.org/x/term v0.10.0
+
+require golang.org/x/sys v0.10.0 // indirect
@@ -0,0 +1,88 @@
+package todo
+
+import (
+	"fmt"
+	"time"
+)
+
+// session represents a period of work on an item
+type session struct {
+	StartedAt time.Time
+	StoppedAt time.Time // empty time while session is running
+}
+
+// Running checks if a work session is in progress on the item
+func (i item) Running() bool {
+	n := len(i.Sessions)
+	return n > 0 && i.Sessions[n-1].StoppedAt.IsZero()
+}
+
+// Tracked returns total time spent on the item, running session counts until now
+func (i item) Tracked(now time.Time) time.Duration {
+	var total time.Duration
+
+	for _, s := range i.Sessions {
+		end := s.StoppedAt
+		if end.IsZero() {
+			end = now
+		}
+		total += end.Sub(s.StartedAt)
+	}
+	return total
+}
+
+// Start begins a work session on item i
+func (l *List) Start(i int) error {
+	ls := *l
+
+	// sanity check the value provided
+	if i <= 0 || i > len(ls) {
+		return fmt.Errorf("item %d does not exist", i)
+	}
+
+	if ls[i-1].Running() {
+		return fmt.Errorf("item %d timer already running", i)
+	}
+
+	now := time.Now()
+	ls[i-1].Sessions = append(ls[i-1].Sessions, session{StartedAt: now})
+	ls[i-1].ModifiedAt = now
+
+	return nil
+}
+
+// Stop ends the running work session on item i
+func (l *List) Stop(i int) error {
+	ls := *l
+
+	// sanity check the value provided
+	if i <= 0 || i > len(ls) {
+		return fmt.Errorf("item %d does not exist", i)
+	}
+
+	if !ls[i-1].Running() {
+		return fmt.Errorf("item %d timer is not running", i)
+	}
+
+	ls[i-1].stopTimer(time.Now())
+
+	return nil
+}
+
+// stopTimer closes running session, if any, at given time
+func (i *item) stopTimer(at time.Time) {
+	if !i.Running() {
+		return
+	}
+	i.Sessions[len(i.Sessions)-1].StoppedAt = at
+	i.ModifiedAt = at
+}
+
+// TotalTracked returns time spent across all items
+func (l *List) TotalTracked(now time.Time) time.Duration {
+	var total time.Duration
+	for _, t := range *l {
+		total += t.Tracked(now)
+	}
+	return total
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"regexp"
 	"time"
 )
 
@@ -14,6 +15,21 @@ type item struct {
 	Done        bool
 	CreatedAt   time.Time
 	CompletedAt time.Time
+	DueDate     time.Time
+	ModifiedAt  time.Time
+	Sessions    []session
+}
+
+// LastModified returns when the item was last changed.
+// items saved before ModifiedAt existed fall back to their other timestamps
+func (i item) LastModified() time.Time {
+	if !i.ModifiedAt.IsZero() {
+		return i.ModifiedAt
+	}
+	if i.CompletedAt.After(i.CreatedAt) {
+		return i.CompletedAt
+	}
+	return i.CreatedAt
 }
 
 // List represent list of all toDo items
@@ -36,10 +52,22 @@ func (l *List) String() string {
 		if t.Done {
 			prefix = "X "
 		}
-		formatted += fmt.Sprintf("%s%d: %s\n", prefix, k+1, t.Task)
+		formatted += fmt.Sprintf("%s%d: %s", prefix, k+1, t.Task)
+		if !t.DueDate.IsZero() {
+			formatted += fmt.Sprintf(" (due %s)", t.DueDate.Format("2006-01-02"))
+		}
+		formatted += "\n"
 
 		if verboseOutput {
 			formatted += fmt.Sprintf("\tCreated: %s\n", t.CreatedAt)
+
+			if len(t.Sessions) > 0 {
+				formatted += fmt.Sprintf("\tTracked: %s", t.Tracked(time.Now()).Round(time.Second))
+				if t.Running() {
+					formatted += " (running)"
+				}
+				formatted += "\n"
+			}
 		}
 	}
 	return formatted
@@ -47,11 +75,19 @@ func (l *List) String() string {
 
 // Add takes care of adding new todo item to list
 func (l *List) Add(task string) {
+	l.AddDue(task, time.Time{})
+}
+
+// AddDue adds new todo item to list with a due date, zero time means no due date
+func (l *List) AddDue(task string, due time.Time) {
+	now := time.Now()
 	t := item{
 		Task:        task,
 		Done:        false,
-		CreatedAt:   time.Now(),
+		CreatedAt:   now,
 		CompletedAt: time.Time{}, //empty time 0000-0000:0000
+		DueDate:     due,
+		ModifiedAt:  now,
 	}
 	// append new item to existing list (modifying underlying pointer value)
 	*l = append(*l, t)
@@ -68,6 +104,26 @@ func (l *List) Complete(i int) error {
 
 	ls[i-1].Done = true
 	ls[i-1].CompletedAt = time.Now()
+	ls[i-1].ModifiedAt = ls[i-1].CompletedAt
+
+	// no more work on a completed item
+	ls[i-1].stopTimer(ls[i-1].CompletedAt)
+
+	return nil
+}
+
+// Uncomplete marks a completed todo item as pending again
+func (l *List) Uncomplete(i int) error {
+	ls := *l
+
+	// sanity check the value provided
+	if i <= 0 || i > len(ls) {
+		return fmt.Errorf("item %d does not exist", i)
+	}
+
+	ls[i-1].Done = false
+	ls[i-1].CompletedAt = time.Time{}
+	ls[i-1].ModifiedAt = time.Now()
 
 	return nil
 }
@@ -86,16 +142,127 @@ func (l *List) Delete(i int) error {
 	return nil
 }
 
+// Match represents a single search hit on the list
+type Match struct {
+	// Item number, usable with Complete and Delete
+	Num  int
+	Task string
+	Done bool
+
+	// start/end byte offsets of every match within Task
+	Loc [][]int
+}
+
+// Highlight wraps every matched section of the task with before and after
+func (m Match) Highlight(before, after string) string {
+	highlighted := ""
+	last := 0
+
+	for _, loc := range m.Loc {
+		highlighted += m.Task[last:loc[0]] + before + m.Task[loc[0]:loc[1]] + after
+		last = loc[1]
+	}
+	return highlighted + m.Task[last:]
+}
+
+// Search looks up items whose task matches expr, ignoring case.
+// expr is treated as a plain substring unless useRegex is set.
+func (l *List) Search(expr string, useRegex bool) ([]Match, error) {
+	if expr == "" {
+		return nil, fmt.Errorf("search expression cannot be blank")
+	}
+
+	if !useRegex {
+		expr = regexp.QuoteMeta(expr)
+	}
+
+	re, err := regexp.Compile("(?i)" + expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid search expression: %w", err)
+	}
+
+	matches := []Match{}
+	for k, t := range *l {
+		// skip empty matches, otherwise expressions like "a*" match every item
+		loc := [][]int{}
+		for _, m := range re.FindAllStringIndex(t.Task, -1) {
+			if m[0] != m[1] {
+				loc = append(loc, m)
+			}
+		}
+		if len(loc) == 0 {
+			continue
+		}
+		matches = append(matches, Match{
+			Num:  k + 1,
+			Task: t.Task,
+			Done: t.Done,
+			Loc:  loc,
+		})
+	}
+
+	return matches, nil
+}
+
+// startOfDay returns midnight of t's day
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// dueItems returns numbers of pending items whose due day passes the check
+func (l *List) dueItems(now time.Time, check func(due, today time.Time) bool) []int {
+	today := startOfDay(now)
+	nums := []int{}
+
+	for k, t := range *l {
+		if t.Done || t.DueDate.IsZero() {
+			continue
+		}
+		if check(startOfDay(t.DueDate.In(now.Location())), today) {
+			nums = append(nums, k+1)
+		}
+	}
+	return nums
+}
+
+// Overdue returns item numbers of pending items due before today
+func (l *List) Overdue(now time.Time) []int {
+	return l.dueItems(now, func(due, today time.Time) bool {
+		return due.Before(today)
+	})
+}
+
+// DueToday returns item numbers of pending items due today
+func (l *List) DueToday(now time.Time) []int {
+	return l.dueItems(now, func(due, today time.Time) bool {
+		return due.Equal(today)
+	})
+}
+
 // Save writes list to a JSON file
+// file is encrypted when TODO_KEYFILE points to a key file
 func (l *List) Save(filename string) error {
 	json, err := json.Marshal(l) //convert list to json bytes
 	if err != nil {
 		return err
 	}
+
+	key, err := readKey()
+	if err != nil {
+		return err
+	}
+
+	if key != nil {
+		if json, err = encrypt(key, json); err != nil {
+			return err
+		}
+		return os.WriteFile(filename, json, 0600)
+	}
+
 	return os.WriteFile(filename, json, 0644)
 }
 
-// Get reads JSON from a file into list
+// Get reads JSON from a file into list, decrypting it if required
 func (l *List) Get(filename string) error {
 	file, err := os.ReadFile(filename)
 	if err != nil {
@@ -111,6 +278,20 @@ func (l *List) Get(filename string) error {
 		return nil
 	}
 
+	// plain JSON files are still readable when a key is set
+	if isEncrypted(file) {
+		key, err := readKey()
+		if err != nil {
+			return err
+		}
+		if key == nil {
+			return ErrNoKey
+		}
+		if file, err = decrypt(key, file); err != nil {
+			return err
+		}
+	}
+
 	return json.Unmarshal(file, l)
 
 }
@@ -1,9 +1,14 @@
 package todo_test
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/karanbirsingh7/pclaig/todo"
 )
@@ -112,3 +117,272 @@ func TestSaveGet(t *testing.T) {
 	}
 
 }
+
+// TestSearch tests substring and regex searches over the list
+func TestSearch(t *testing.T) {
+	l := todo.List{}
+	l.Add("Buy milk")
+	l.Add("Call the bank")
+	l.Add("buy a new MILK jug")
+
+	testCases := []struct {
+		name     string
+		expr     string
+		useRegex bool
+		expNums  []int
+		expErr   bool
+	}{
+		{"SubstringIgnoreCase", "milk", false, []int{1, 3}, false},
+		{"SubstringNoMatch", "groceries", false, []int{}, false},
+		{"SubstringMetaChars", "b.y", false, []int{}, false},
+		{"Regex", "^b.y", true, []int{1, 3}, false},
+		{"RegexAnchored", "bank$", true, []int{2}, false},
+		{"RegexInvalid", "milk(", true, nil, true},
+		{"Blank", "", false, nil, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			matches, err := l.Search(tc.expr, tc.useRegex)
+			if tc.expErr {
+				if err == nil {
+					t.Fatal("Expected error, got nil instead")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(matches) != len(tc.expNums) {
+				t.Fatalf("Expected %d matches, got %d instead", len(tc.expNums), len(matches))
+			}
+			for i, m := range matches {
+				if m.Num != tc.expNums[i] {
+					t.Errorf("Got item %d, want %d", m.Num, tc.expNums[i])
+				}
+			}
+		})
+	}
+}
+
+// TestMatchHighlight tests that every match is wrapped by the markers
+func TestMatchHighlight(t *testing.T) {
+	l := todo.List{}
+	l.Add("Milk and more milk")
+
+	matches, err := l.Search("milk", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "[Milk] and more [milk]"
+	if got := matches[0].Highlight("[", "]"); got != expected {
+		t.Errorf("Got %q, want %q", got, expected)
+	}
+}
+
+// TestSaveGetEncrypted tests saving and reading back an encrypted list
+func TestSaveGetEncrypted(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	wrongKeyFile := filepath.Join(dir, "wrongkey")
+	todoFile := filepath.Join(dir, "todo.json")
+
+	if err := os.WriteFile(keyFile, []byte("my secret key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(wrongKeyFile, []byte("not my key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	taskName := "Call customer ACME Corp"
+	l1 := todo.List{}
+	l1.Add(taskName)
+
+	t.Setenv("TODO_KEYFILE", keyFile)
+	if err := l1.Save(todoFile); err != nil {
+		t.Fatalf("Error saving list to file: %s", err)
+	}
+
+	data, err := os.ReadFile(todoFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte(taskName)) {
+		t.Errorf("Task %q found in plain text in encrypted file", taskName)
+	}
+
+	t.Run("RightKey", func(t *testing.T) {
+		l2 := todo.List{}
+		if err := l2.Get(todoFile); err != nil {
+			t.Fatalf("Error reading from file: %s", err)
+		}
+		if l2[0].Task != taskName {
+			t.Errorf("Got %q, want %q", l2[0].Task, taskName)
+		}
+	})
+
+	t.Run("WrongKey", func(t *testing.T) {
+		t.Setenv("TODO_KEYFILE", wrongKeyFile)
+		l2 := todo.List{}
+		if err := l2.Get(todoFile); !errors.Is(err, todo.ErrWrongKey) {
+			t.Errorf("Expected error %q, got %q instead", todo.ErrWrongKey, err)
+		}
+	})
+
+	t.Run("NoKey", func(t *testing.T) {
+		t.Setenv("TODO_KEYFILE", "")
+		l2 := todo.List{}
+		if err := l2.Get(todoFile); !errors.Is(err, todo.ErrNoKey) {
+			t.Errorf("Expected error %q, got %q instead", todo.ErrNoKey, err)
+		}
+	})
+}
+
+// TestUncomplete tests marking a completed item as pending again
+func TestUncomplete(t *testing.T) {
+	l := todo.List{}
+	l.Add("New Task")
+
+	if err := l.Complete(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Uncomplete(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if l[0].Done {
+		t.Errorf("Task should be pending")
+	}
+	if !l[0].CompletedAt.IsZero() {
+		t.Errorf("Completion time should be reset, got %s", l[0].CompletedAt)
+	}
+
+	if err := l.Uncomplete(2); err == nil {
+		t.Errorf("Expected error for missing item, got nil instead")
+	}
+}
+
+// TestLastModified tests modification time is tracked on changes
+func TestLastModified(t *testing.T) {
+	l := todo.List{}
+	l.Add("New Task")
+
+	created := l[0].LastModified()
+	if !created.Equal(l[0].CreatedAt) {
+		t.Errorf("Got %s, want creation time %s", created, l[0].CreatedAt)
+	}
+
+	l.Complete(1)
+	if !l[0].LastModified().Equal(l[0].CompletedAt) {
+		t.Errorf("Got %s, want completion time %s", l[0].LastModified(), l[0].CompletedAt)
+	}
+
+	// lists saved before ModifiedAt existed
+	l[0].ModifiedAt = time.Time{}
+	if !l[0].LastModified().Equal(l[0].CompletedAt) {
+		t.Errorf("Got %s, want completion time %s", l[0].LastModified(), l[0].CompletedAt)
+	}
+}
+
+// TestOverdueDueToday tests due date checks against a fixed clock
+func TestOverdueDueToday(t *testing.T) {
+	now := time.Date(2026, time.October, 14, 15, 30, 0, 0, time.UTC)
+	day := func(d int) time.Time {
+		return time.Date(2026, time.October, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	l := todo.List{}
+	l.AddDue("overdue", day(12))
+	l.AddDue("due today", day(14))
+	l.AddDue("due tomorrow", day(15))
+	l.Add("no due date")
+	l.AddDue("overdue but done", day(10))
+	l.Complete(5)
+
+	testCases := []struct {
+		name     string
+		got      []int
+		expected []int
+	}{
+		{"Overdue", l.Overdue(now), []int{1}},
+		{"DueToday", l.DueToday(now), []int{2}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if fmt.Sprint(tc.got) != fmt.Sprint(tc.expected) {
+				t.Errorf("Got %v, want %v", tc.got, tc.expected)
+			}
+		})
+	}
+}
+
+// TestStartStop tests tracking work sessions on an item
+func TestStartStop(t *testing.T) {
+	l := todo.List{}
+	l.Add("New Task")
+
+	if err := l.Stop(1); err == nil {
+		t.Errorf("Expected error stopping timer not running, got nil instead")
+	}
+
+	if err := l.Start(1); err != nil {
+		t.Fatal(err)
+	}
+	if !l[0].Running() {
+		t.Errorf("Expected timer to be running")
+	}
+	if err := l.Start(1); err == nil {
+		t.Errorf("Expected error starting timer twice, got nil instead")
+	}
+
+	if err := l.Stop(1); err != nil {
+		t.Fatal(err)
+	}
+	if l[0].Running() {
+		t.Errorf("Expected timer to be stopped")
+	}
+
+	// completing an item stops its timer
+	l.Start(1)
+	l.Complete(1)
+	if l[0].Running() {
+		t.Errorf("Expected timer to be stopped on complete")
+	}
+	if len(l[0].Sessions) != 2 {
+		t.Errorf("Expected %d sessions, got %d instead", 2, len(l[0].Sessions))
+	}
+
+	if err := l.Start(2); err == nil {
+		t.Errorf("Expected error for missing item, got nil instead")
+	}
+}
+
+// TestTracked tests time accumulated over sessions
+func TestTracked(t *testing.T) {
+	start := time.Date(2026, time.October, 14, 9, 0, 0, 0, time.UTC)
+
+	l := todo.List{}
+	l.Add("Task 1")
+	l.Add("Task 2")
+	l.Start(1)
+	l.Start(2)
+
+	// first session 30 minutes, second one still running
+	l[0].Sessions[0].StartedAt = start
+	l[0].Sessions[0].StoppedAt = start.Add(30 * time.Minute)
+	l[1].Sessions[0].StartedAt = start
+
+	now := start.Add(time.Hour)
+	if got := l[0].Tracked(now); got != 30*time.Minute {
+		t.Errorf("Got %s, want %s", got, 30*time.Minute)
+	}
+	if got := l[1].Tracked(now); got != time.Hour {
+		t.Errorf("Got %s, want %s", got, time.Hour)
+	}
+	if got := l.TotalTracked(now); got != 90*time.Minute {
+		t.Errorf("Got %s, want %s", got, 90*time.Minute)
+	}
+}
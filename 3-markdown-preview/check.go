@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/russross/blackfriday/v2"
+)
+
+var (
+	// fenceRe matches code fence lines, up to 3 spaces indented
+	fenceRe = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})(.*)$")
+
+	// atxRe matches ATX heading lines, also in block quotes
+	atxRe = regexp.MustCompile(`^ {0,3}(?:>[ \t]*)*#{1,6} `)
+
+	// setextRe matches setext heading underlines
+	setextRe = regexp.MustCompile(`^ {0,3}(?:=+|-+)[ \t]*$`)
+)
+
+// problem is an issue found checking a document, line starts at 1
+type problem struct {
+	line int
+	msg  string
+}
+
+// runCheck reports problems in markdown read from in to out, without
+// generating HTML. It returns an error when any problem is found
+func runCheck(filename string, in io.Reader, out io.Writer) error {
+	input, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+
+	problems, err := checkContent(input, filename)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range problems {
+		fmt.Fprintf(out, "%s:%d: %s\n", filename, p.line, p.msg)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s: %d problem(s) found", filename, len(problems))
+	}
+	return nil
+}
+
+// checkFile reports problems in markdown file filename, like runCheck
+func checkFile(filename string, out io.Writer) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return runCheck(filename, f, out)
+}
+
+// checkContent finds broken relative links, missing images, duplicate heading
+// anchors and unclosed code fences. Relative paths are checked from the
+// directory of filename
+func checkContent(input []byte, filename string) ([]problem, error) {
+	_, body, err := splitFrontMatter(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// lines of front matter, so reported lines match the file
+	offset := bytes.Count(input[:len(input)-len(body)], []byte("\n"))
+	lines := strings.Split(string(body), "\n")
+
+	problems := checkFences(lines)
+
+	doc := blackfriday.New(blackfriday.WithExtensions(markdownExtensions)).Parse(body)
+
+	// headings are paired with heading lines by position, as their text
+	// loses inline markup
+	headingLines := findHeadings(lines)
+	anchors := map[string]int{}
+	n, line := 0, 1
+
+	doc.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		if !entering || node.Type != blackfriday.Heading || node.IsTitleblock {
+			return blackfriday.GoToNext
+		}
+
+		// unmatched headings keep the last known line
+		if n < len(headingLines) {
+			line = headingLines[n]
+		}
+		n++
+
+		if first, ok := anchors[node.HeadingID]; ok {
+			problems = append(problems, problem{line, fmt.Sprintf("duplicate heading anchor #%s, first defined on line %d", node.HeadingID, first+offset)})
+			return blackfriday.SkipChildren
+		}
+		anchors[node.HeadingID] = line
+		return blackfriday.SkipChildren
+	})
+
+	// links can point to renamed duplicates, such as #id-1
+	ids := map[string]bool{}
+	for _, h := range uniqueHeadings(doc) {
+		ids[h.id] = true
+	}
+
+	linkLine := newLineFinder(lines)
+	dir := filepath.Dir(filename)
+
+	doc.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		// footnote references keep their label as destination
+		if !entering || (node.Type != blackfriday.Link && node.Type != blackfriday.Image) || node.NoteID != 0 {
+			return blackfriday.GoToNext
+		}
+
+		dest := string(node.LinkData.Destination)
+		line := linkLine.find(dest)
+
+		if msg := checkDestination(dest, dir, node.Type == blackfriday.Image, ids); msg != "" {
+			problems = append(problems, problem{line, msg})
+		}
+		return blackfriday.GoToNext
+	})
+
+	for i := range problems {
+		problems[i].line += offset
+	}
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].line < problems[j].line
+	})
+
+	return problems, nil
+}
+
+// checkDestination checks a link or image destination, returning a problem
+// message or an empty string when it is fine
+func checkDestination(dest, dir string, image bool, ids map[string]bool) string {
+	u, err := url.Parse(dest)
+	if err != nil {
+		return fmt.Sprintf("invalid link %q: %v", dest, err)
+	}
+
+	// remote URLs and root relative paths can't be checked locally
+	if u.Scheme != "" || u.Host != "" || strings.HasPrefix(u.Path, "/") {
+		return ""
+	}
+
+	if u.Path == "" {
+		if u.Fragment != "" && !ids[u.Fragment] {
+			return fmt.Sprintf("broken anchor #%s", u.Fragment)
+		}
+		return ""
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(u.Path))); err == nil {
+		return ""
+	}
+
+	if image {
+		return fmt.Sprintf("missing image %s", u.Path)
+	}
+	return fmt.Sprintf("broken link %s", u.Path)
+}
+
+// checkFences reports code fences that are never closed
+func checkFences(lines []string) []problem {
+	problems := []problem{}
+
+	open, openLine := "", 0
+	for i, l := range lines {
+		m := fenceRe.FindStringSubmatch(l)
+		if m == nil {
+			continue
+		}
+
+		if open == "" {
+			open, openLine = m[1], i+1
+			continue
+		}
+
+		// closing fence uses the same character, at least as long, with no info
+		if m[1][0] == open[0] && len(m[1]) >= len(open) && strings.TrimSpace(m[2]) == "" {
+			open = ""
+		}
+	}
+
+	if open != "" {
+		problems = append(problems, problem{openLine, "unclosed code fence " + open})
+	}
+	return problems
+}
+
+// lineFinder locates text in lines, continuing from the last match
+type lineFinder struct {
+	lines []string
+	next  int
+}
+
+// newLineFinder - constructor for lineFinder
+func newLineFinder(lines []string) *lineFinder {
+	return &lineFinder{lines: lines}
+}
+
+// find returns the line number of the next line containing text, or the
+// line of the last match when text isn't found
+func (f *lineFinder) find(text string) int {
+	for i := f.next; i < len(f.lines); i++ {
+		if strings.Contains(f.lines[i], text) {
+			f.next = i
+			return i + 1
+		}
+	}
+	return f.next + 1
+}
+
+// findHeadings returns the numbers of ATX heading lines and setext heading
+// text lines, in order, skipping code blocks
+func findHeadings(lines []string) []int {
+	code := codeLines(lines)
+	headings := []int{}
+
+	for i, l := range lines {
+		if code[i] {
+			continue
+		}
+		if atxRe.MatchString(l) {
+			headings = append(headings, i+1)
+			continue
+		}
+
+		if strings.TrimSpace(l) == "" || i+1 >= len(lines) || code[i+1] || fenceRe.MatchString(l) {
+			continue
+		}
+		if setextRe.MatchString(lines[i+1]) && !setextRe.MatchString(l) {
+			headings = append(headings, i+1)
+		}
+	}
+	return headings
+}
+
+// codeLines marks lines of fenced code blocks, fences included. An unclosed
+// fence isn't a code block
+func codeLines(lines []string) []bool {
+	code := make([]bool, len(lines))
+
+	open, openLine := "", 0
+	for i, l := range lines {
+		m := fenceRe.FindStringSubmatch(l)
+		if m == nil {
+			continue
+		}
+
+		if open == "" {
+			open, openLine = m[1], i
+			continue
+		}
+
+		if m[1][0] == open[0] && len(m[1]) >= len(open) && strings.TrimSpace(m[2]) == "" {
+			for j := openLine; j <= i; j++ {
+				code[j] = true
+			}
+			open = ""
+		}
+	}
+	return code
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const checkInput = `---
+title: Check
+---
+# Intro
+
+See [other](other.md), [missing](missing.md#top) and [remote](https://example.com).
+
+![logo](logo.png) ![gone](img/gone.png)
+
+Jump to [setup](#setup), [second intro](#intro-1) or [nowhere](#nowhere)[^1].
+
+[^1]: Footnotes aren't links to check.
+
+Setup
+-----
+
+# Intro
+
+` + "```go" + `
+func main() {}
+`
+
+func TestRunCheck(t *testing.T) {
+	dir := t.TempDir()
+	for _, f := range []string{"other.md", "logo.png"} {
+		if err := os.WriteFile(filepath.Join(dir, f), []byte{}, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	filename := filepath.Join(dir, "doc.md")
+
+	var out bytes.Buffer
+	err := runCheck(filename, strings.NewReader(checkInput), &out)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "5 problem(s) found") {
+		t.Errorf("Unexpected error %q", err)
+	}
+
+	expected := filename + ":6: broken link missing.md\n" +
+		filename + ":8: missing image img/gone.png\n" +
+		filename + ":10: broken anchor #nowhere\n" +
+		filename + ":17: duplicate heading anchor #intro, first defined on line 4\n" +
+		filename + ":19: unclosed code fence ```\n"
+
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, out.String())
+	}
+}
+
+func TestRunCheckHeadingLines(t *testing.T) {
+	input := "# Intro\n" +
+		"\n" +
+		"```md\n" +
+		"# **Bold** heading\n" +
+		"```\n" +
+		"# **Bold** heading\n" +
+		"\n" +
+		"*Bold* `heading`\n" +
+		"=================\n" +
+		"\n" +
+		"> # Bold heading\n"
+
+	var out bytes.Buffer
+	if err := runCheck("doc.md", strings.NewReader(input), &out); err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	expected := "doc.md:8: duplicate heading anchor #bold-heading, first defined on line 6\n" +
+		"doc.md:11: duplicate heading anchor #bold-heading, first defined on line 6\n"
+
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, out.String())
+	}
+}
+
+func TestRunCheckClean(t *testing.T) {
+	var out bytes.Buffer
+	if err := runCheck(inputFile, strings.NewReader("# Title\n\n[top](#title)\n\n```\ncode\n```\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.String() != "" {
+		t.Errorf("Expected no output, got %q", out.String())
+	}
+}
+
+func TestCheckFences(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		expLen int
+	}{
+		{"Closed", "```go\ncode\n```\n", 0},
+		{"Tilde", "~~~\ncode\n~~~~\n", 0},
+		{"LongerFence", "````\n```\n````\n", 0},
+		{"ShorterClose", "````\ncode\n```\n", 1},
+		{"OtherChar", "```\ncode\n~~~\n", 1},
+		{"InfoIsNotClose", "```\ncode\n```go\n", 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			problems := checkFences(strings.Split(tc.input, "\n"))
+			if len(problems) != tc.expLen {
+				t.Errorf("Expected %d problems, got %v", tc.expLen, problems)
+			}
+		})
+	}
+}
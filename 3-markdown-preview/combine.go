@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/russross/blackfriday/v2"
+)
+
+// source is a markdown document to render, name is its file name
+type source struct {
+	name  string
+	input []byte
+}
+
+// docFile describes a rendered file, available in templates as .Files to
+// build navigation, for example:
+//
+//	{{ range .Files }}<a href="#{{ .ID }}">{{ .Title }}</a>{{ end }}
+type docFile struct {
+	Name  string
+	Title string
+
+	// section anchor, empty when a single file is rendered
+	ID string
+}
+
+// readSources reads markdown files in order
+func readSources(filenames []string) ([]source, error) {
+	srcs := make([]source, 0, len(filenames))
+	for _, f := range filenames {
+		input, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		srcs = append(srcs, source{name: f, input: input})
+	}
+	return srcs, nil
+}
+
+// combine parses srcs into a single document. With several sources each one
+// is wrapped in a <section> with its own anchor. It returns the front matter
+// of the first source, which applies to the whole document
+func combine(srcs []source) (*blackfriday.Node, map[string]interface{}, []docFile, error) {
+	if len(srcs) == 1 {
+		meta, input, err := splitFrontMatter(srcs[0].input)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		doc := parseMarkdown(input)
+		return doc, meta, []docFile{newDocFile(srcs[0].name, meta, doc, "")}, nil
+	}
+
+	combined := blackfriday.NewNode(blackfriday.Document)
+	files := []docFile{}
+	seen := map[string]int{}
+
+	var docMeta map[string]interface{}
+
+	for i, src := range srcs {
+		meta, input, err := splitFrontMatter(src.input)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: %w", src.name, err)
+		}
+		if i == 0 {
+			docMeta = meta
+		}
+
+		id := "file-" + blackfriday.SanitizedAnchorName(strings.TrimSuffix(filepath.Base(src.name), filepath.Ext(src.name)))
+		if count, ok := seen[id]; ok {
+			seen[id] = count + 1
+			id = fmt.Sprintf("%s-%d", id, count+1)
+		}
+		seen[id] = 0
+
+		doc := parseMarkdown(input)
+		files = append(files, newDocFile(src.name, meta, doc, id))
+
+		// footnotes of every file start at 1, and relative paths are
+		// resolved from the first file like the rest of the document
+		prefixFootnotes(doc, id+"-")
+		if i > 0 {
+			rebaseLinks(doc, filepath.Dir(src.name), filepath.Dir(srcs[0].name))
+		}
+
+		open := blackfriday.NewNode(blackfriday.HTMLBlock)
+		open.Literal = []byte(`<section id="` + id + `">`)
+		combined.AppendChild(open)
+
+		for child := doc.FirstChild; child != nil; {
+			next := child.Next
+			child.Unlink()
+			combined.AppendChild(child)
+			child = next
+		}
+
+		end := blackfriday.NewNode(blackfriday.HTMLBlock)
+		end.Literal = []byte("</section>")
+		combined.AppendChild(end)
+	}
+
+	return combined, docMeta, files, nil
+}
+
+// prefixFootnotes adds prefix to footnote references and definitions of doc,
+// so their anchors don't clash with other files
+func prefixFootnotes(doc *blackfriday.Node, prefix string) {
+	doc.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		if !entering {
+			return blackfriday.GoToNext
+		}
+
+		if node.Type == blackfriday.Link && node.NoteID != 0 {
+			node.Destination = append([]byte(prefix), node.Destination...)
+		}
+		if node.Type == blackfriday.Item && node.RefLink != nil {
+			node.RefLink = append([]byte(prefix), node.RefLink...)
+		}
+		return blackfriday.GoToNext
+	})
+}
+
+// rebaseLinks rewrites relative link and image destinations of doc, a file
+// in dir, to be relative to base
+func rebaseLinks(doc *blackfriday.Node, dir, base string) {
+	doc.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		if !entering || (node.Type != blackfriday.Link && node.Type != blackfriday.Image) || node.NoteID != 0 {
+			return blackfriday.GoToNext
+		}
+
+		if dest, ok := rebase(string(node.Destination), dir, base); ok {
+			node.Destination = []byte(dest)
+		}
+		return blackfriday.GoToNext
+	})
+}
+
+// rebase returns relative destination dest, from dir, as a path from base.
+// Fragments and queries are kept, ok is false for other destinations
+func rebase(dest, dir, base string) (string, bool) {
+	u, err := url.Parse(dest)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" || strings.HasPrefix(u.Path, "/") {
+		return "", false
+	}
+
+	// path as written, keeping its escaping
+	path, suffix := dest, ""
+	if i := strings.IndexAny(dest, "?#"); i >= 0 {
+		path, suffix = dest[:i], dest[i:]
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(absBase, filepath.Join(absDir, filepath.FromSlash(path)))
+	if err != nil {
+		return "", false
+	}
+	return filepath.ToSlash(rel) + suffix, true
+}
+
+// parseMarkdown parses input with the extensions and transforms mdp supports
+func parseMarkdown(input []byte) *blackfriday.Node {
+	doc := blackfriday.New(blackfriday.WithExtensions(markdownExtensions)).Parse(input)
+	gfmTransform(doc)
+	return doc
+}
+
+// newDocFile - constructor for docFile, title comes from front matter,
+// then the first heading, then the file name
+func newDocFile(name string, meta map[string]interface{}, doc *blackfriday.Node, id string) docFile {
+	f := docFile{
+		Name:  filepath.Base(name),
+		Title: metaTitle(meta),
+		ID:    id,
+	}
+
+	if f.Title == "" {
+		doc.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+			if entering && node.Type == blackfriday.Heading && !node.IsTitleblock {
+				f.Title = nodeText(node)
+				return blackfriday.Terminate
+			}
+			return blackfriday.GoToNext
+		})
+	}
+
+	if f.Title == "" {
+		f.Title = f.Name
+	}
+	return f
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSources(t *testing.T) {
+	srcs := []source{
+		{name: "notes/v1.0.md", input: []byte("---\ntitle: Release Notes\n---\n# Features\n\n- one\n")},
+		{name: "notes/v1.1.md", input: []byte("# Features\n\n- two\n")},
+		{name: "other/v1.1.md", input: []byte("---\ntitle: Hotfix\n---\nno heading\n")},
+	}
+
+	tmpl := writeTemplate(t, `{{ .Title }}|{{ .Filename }}
+{{ range .Files }}{{ .ID }}|{{ .Name }}|{{ .Title }}
+{{ end }}{{ .Body }}`)
+
+	result, err := parseSources(srcs, config{template: tmpl})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `Release Notes|v1.0.md, v1.1.md, v1.1.md
+file-v1-0|v1.0.md|Release Notes
+file-v1-1|v1.1.md|Features
+file-v1-1-1|v1.1.md|Hotfix
+<section id="file-v1-0">
+
+<h1 id="features">Features</h1>
+
+<ul>
+<li>one</li>
+</ul>
+
+</section>
+
+<section id="file-v1-1">
+
+<h1 id="features-1">Features</h1>
+
+<ul>
+<li>two</li>
+</ul>
+
+</section>
+
+<section id="file-v1-1-1">
+
+<p>no heading</p>
+
+</section>
+`
+	if string(result) != expected {
+		t.Errorf("Got:\n%s\nWant:\n%s", result, expected)
+	}
+}
+
+func TestParseContentFiles(t *testing.T) {
+	// a single file isn't wrapped in a section
+	result, err := parseContent([]byte("plain text\n"), "doc.md", config{template: writeTemplate(t, "{{ range .Files }}{{ .ID }}|{{ .Title }}{{ end }}\n{{ .Body }}")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "|doc.md\n<p>plain text</p>\n"
+	if string(result) != expected {
+		t.Errorf("Got %q, want %q", result, expected)
+	}
+}
+
+func TestRunFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{filepath.Join(dir, "a.md"), filepath.Join(dir, "b.md")}
+	for i, f := range files {
+		if err := os.WriteFile(f, []byte("# Part "+string(rune('A'+i))+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := runFiles(files, &out, "-", false, config{}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"<title>Part A</title>",
+		"<p>file: a.md, b.md</p>",
+		`<section id="file-a">`,
+		`<h1 id="part-b">Part B</h1>`,
+	}
+	for _, exp := range expected {
+		if !strings.Contains(out.String(), exp) {
+			t.Errorf("Expected %q in %q", exp, out.String())
+		}
+	}
+
+	if err := runFiles(append(files, filepath.Join(dir, "missing.md")), &out, "-", false, config{}); err == nil {
+		t.Error("Expected error for missing file, got nil")
+	}
+}
+
+func TestParseSourcesFootnotes(t *testing.T) {
+	srcs := []source{
+		{name: "a.md", input: []byte("A note[^1].\n\n[^1]: From a.\n")},
+		{name: "b.md", input: []byte("B note[^1].\n\n[^1]: From b.\n")},
+	}
+
+	result, err := parseSources(srcs, config{template: writeTemplate(t, "{{ .Body }}")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// each file links its own footnote
+	expected := []string{
+		`href="#fn:file-a-1"`, `id="fn:file-a-1"`,
+		`href="#fn:file-b-1"`, `id="fn:file-b-1"`,
+	}
+	for _, exp := range expected {
+		if strings.Count(string(result), exp) != 1 {
+			t.Errorf("Expected %q once in %q", exp, result)
+		}
+	}
+}
+
+func TestRunFilesRelativePaths(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "rel", "sub")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// 1x1 transparent GIF
+	gif := []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00;")
+	files := map[string][]byte{
+		filepath.Join(dir, "a.md"):    []byte("# A\n\n[c](rel/sub/c.md)\n"),
+		filepath.Join(sub, "c.md"):    []byte("# C\n\n![i](pic.gif) [a](../../a.md#a) [top](#c) [web](https://example.com/x.md)\n"),
+		filepath.Join(sub, "pic.gif"): gif,
+	}
+	for f, data := range files {
+		if err := os.WriteFile(f, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := runFiles([]string{filepath.Join(dir, "a.md"), filepath.Join(sub, "c.md")}, &out, "-", false, config{}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`<a href="rel/sub/c.md" rel="nofollow">c</a>`,
+		`<img src="rel/sub/pic.gif" alt="i"/>`,
+		`<a href="a.md#a" rel="nofollow">a</a>`,
+		`<a href="#c" rel="nofollow">top</a>`,
+		`<a href="https://example.com/x.md"`,
+	}
+	for _, exp := range expected {
+		if !strings.Contains(out.String(), exp) {
+			t.Errorf("Expected %q in %q", exp, out.String())
+		}
+	}
+
+	// standalone export finds images of every file
+	out.Reset()
+	if err := runFiles([]string{filepath.Join(dir, "a.md"), filepath.Join(sub, "c.md")}, &out, "-", false, config{standalone: true}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `src="data:image/gif;base64,R0lGODlhAQABAAAAADs="`) {
+		t.Errorf("Expected inlined image in %q", out.String())
+	}
+}
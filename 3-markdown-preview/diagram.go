@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/russross/blackfriday/v2"
+)
+
+// diagramPrefix marks where a rendered diagram goes in the body. Diagrams
+// are inserted after sanitizing, the sanitizer would strip SVG elements,
+// so placeholders are plain text every policy keeps
+const diagramPrefix = "mdp-diagram-"
+
+// hooks maps fenced block languages to the command rendering them into SVG,
+// the block content is the command input. Set as -hook lang=command
+type hooks map[string][]string
+
+// String - implements flag.Value
+func (h hooks) String() string {
+	langs := make([]string, 0, len(h))
+	for lang, cmd := range h {
+		langs = append(langs, lang+"="+strings.Join(cmd, " "))
+	}
+	sort.Strings(langs)
+	return strings.Join(langs, ",")
+}
+
+// Set - implements flag.Value, adds a lang=command hook
+func (h hooks) Set(value string) error {
+	lang, cmdLine, ok := strings.Cut(value, "=")
+	cmd := strings.Fields(cmdLine)
+	if !ok || strings.TrimSpace(lang) == "" || len(cmd) == 0 {
+		return fmt.Errorf("invalid hook %q, expected lang=command", value)
+	}
+
+	h[strings.TrimSpace(lang)] = cmd
+	return nil
+}
+
+// renderDiagrams runs hooks for fenced blocks of doc, replacing each block
+// with a placeholder. It returns the SVG for every placeholder.
+// blocks failing to render are kept as code blocks
+func renderDiagrams(doc *blackfriday.Node, h hooks, cacheDir string) map[string][]byte {
+	diagrams := map[string][]byte{}
+	if len(h) == 0 {
+		return diagrams
+	}
+
+	blocks := []*blackfriday.Node{}
+	doc.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		if entering && node.Type == blackfriday.CodeBlock && len(node.Info) > 0 {
+			blocks = append(blocks, node)
+		}
+		return blackfriday.GoToNext
+	})
+
+	// nodes are replaced after walking, so the walk isn't disturbed
+	for _, node := range blocks {
+		cmd, ok := h[strings.Fields(string(node.Info))[0]]
+		if !ok {
+			continue
+		}
+
+		key := diagramKey(cmd, node.Literal)
+		svg, err := renderDiagram(cmd, node.Literal, key, cacheDir)
+		if err != nil {
+			continue
+		}
+		diagrams[key] = svg
+
+		placeholder := blackfriday.NewNode(blackfriday.HTMLBlock)
+		placeholder.Literal = []byte(diagramPrefix + key)
+		node.InsertBefore(placeholder)
+		node.Unlink()
+	}
+
+	return diagrams
+}
+
+// diagramKey hashes the command and block content, identifying the output
+func diagramKey(cmd []string, input []byte) string {
+	h := sha256.New()
+	for _, arg := range cmd {
+		h.Write([]byte(arg))
+		h.Write([]byte{0})
+	}
+	h.Write(input)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// renderDiagram returns SVG from the cache, or runs cmd with input and
+// caches the result. An empty cacheDir disables caching
+func renderDiagram(cmd []string, input []byte, key, cacheDir string) ([]byte, error) {
+	cacheFile := filepath.Join(cacheDir, key+".svg")
+	if cacheDir != "" {
+		if svg, err := os.ReadFile(cacheFile); err == nil {
+			return svg, nil
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	c := command(cmd[0], cmd[1:]...)
+	c.Stdin = bytes.NewReader(input)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+
+	if err := c.Run(); err != nil {
+		return nil, fmt.Errorf("diagram command %q failed: %w: %s", strings.Join(cmd, " "), err, stderr.Bytes())
+	}
+
+	// drop XML declaration and doctype, they are invalid inside HTML
+	svg := stdout.Bytes()
+	i := bytes.Index(svg, []byte("<svg"))
+	if i < 0 {
+		return nil, fmt.Errorf("diagram command %q returned no SVG", strings.Join(cmd, " "))
+	}
+	svg = svg[i:]
+
+	if cacheDir != "" {
+		// cache is best effort, diagram is still rendered when it fails
+		if err := os.MkdirAll(cacheDir, 0755); err == nil {
+			os.WriteFile(cacheFile, svg, 0644)
+		}
+	}
+	return svg, nil
+}
+
+// insertDiagrams replaces placeholders in body with their SVG
+func insertDiagrams(body []byte, diagrams map[string][]byte) []byte {
+	for key, svg := range diagrams {
+		placeholder := []byte(diagramPrefix + key)
+		diagram := append([]byte(`<div class="diagram">`), svg...)
+		diagram = append(diagram, "</div>"...)
+		body = bytes.ReplaceAll(body, placeholder, diagram)
+	}
+	return body
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestDiagrams(t *testing.T) {
+	command = mockCommand
+	defer func() { command = exec.Command }()
+
+	testCases := []struct {
+		name   string
+		input  string
+		policy string
+		exp    string
+	}{
+		{"Rendered", "```dot\ndigraph { a -> b }\n```\n", "",
+			`<div class="diagram"><svg><text>[dot -Tsvg] digraph { a -> b }</text></svg>` + "\n</div>"},
+		{"RenderedStrict", "```dot\ndigraph { a -> b }\n```\n", policyStrict,
+			`<div class="diagram"><svg>`},
+		{"Fallback", "```dot\ninvalid\n```\n", "",
+			`<pre><code class="language-dot">invalid`},
+		{"OtherLanguage", "```unknown\ndigraph\n```\n", "",
+			`<pre><code class="language-unknown">digraph`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := config{
+				template: writeTemplate(t, "{{ .Body }}"),
+				policy:   tc.policy,
+				hooks:    hooks{"dot": {"dot", "-Tsvg"}},
+			}
+
+			result, err := parseContent([]byte(tc.input), "diagram.md", cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(string(result), tc.exp) {
+				t.Errorf("Expected %q in %q", tc.exp, result)
+			}
+			if strings.Contains(string(result), diagramPrefix) {
+				t.Errorf("Unexpected placeholder in %q", result)
+			}
+		})
+	}
+}
+
+func TestDiagramCache(t *testing.T) {
+	runs := 0
+	command = func(exe string, args ...string) *exec.Cmd {
+		runs++
+		return mockCommand(exe, args...)
+	}
+	defer func() { command = exec.Command }()
+
+	cfg := config{
+		template: writeTemplate(t, "{{ .Body }}"),
+		hooks:    hooks{"dot": {"dot", "-Tsvg"}},
+		cacheDir: t.TempDir(),
+	}
+
+	input := []byte("```dot\ndigraph { a -> b }\n```\n")
+	first, err := parseContent(input, "diagram.md", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := parseContent(input, "diagram.md", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if runs != 1 {
+		t.Errorf("Expected command to run once, ran %d times", runs)
+	}
+	if string(first) != string(second) {
+		t.Errorf("Expected cached diagram %q, got %q", first, second)
+	}
+
+	// a different diagram isn't cached
+	if _, err := parseContent([]byte("```dot\ndigraph { b -> c }\n```\n"), "diagram.md", cfg); err != nil {
+		t.Fatal(err)
+	}
+	if runs != 2 {
+		t.Errorf("Expected command to run twice, ran %d times", runs)
+	}
+}
+
+func TestHookFlag(t *testing.T) {
+	h := hooks{}
+
+	if err := h.Set("dot=dot -Tsvg"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Set("mermaid = mmdc -i - -o -"); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := "dot=dot -Tsvg,mermaid=mmdc -i - -o -"
+	if h.String() != exp {
+		t.Errorf("Expected %q, got %q", exp, h.String())
+	}
+
+	for _, invalid := range []string{"dot", "dot=", "=dot -Tsvg"} {
+		if err := h.Set(invalid); err == nil {
+			t.Errorf("Expected error for hook %q, got nil", invalid)
+		}
+	}
+}
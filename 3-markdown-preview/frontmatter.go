@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// front matter delimiters, must be the very first line of the file
+var (
+	yamlDelim = []byte("---")
+	tomlDelim = []byte("+++")
+)
+
+// splitFrontMatter separates YAML (---) or TOML (+++) front matter from the
+// markdown body. Input without front matter, without a closing delimiter or
+// with YAML that isn't a mapping returns a nil map and input as is
+func splitFrontMatter(input []byte) (map[string]interface{}, []byte, error) {
+	first, rest, ok := cutLine(input)
+	if !ok {
+		return nil, input, nil
+	}
+
+	delim := bytes.TrimSpace(first)
+	if !bytes.Equal(delim, yamlDelim) && !bytes.Equal(delim, tomlDelim) {
+		return nil, input, nil
+	}
+
+	// look for closing delimiter line
+	var raw []byte
+	body := rest
+	for {
+		line, next, more := cutLine(body)
+		if bytes.Equal(bytes.TrimSpace(line), delim) {
+			raw = rest[:len(rest)-len(body)]
+			body = next
+			break
+		}
+		if !more {
+			// a document can start with a horizontal rule
+			return nil, input, nil
+		}
+		body = next
+	}
+
+	meta := map[string]interface{}{}
+	if bytes.Equal(delim, yamlDelim) {
+		err := yaml.Unmarshal(raw, &meta)
+
+		// text between two horizontal rules isn't a mapping
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			return nil, input, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid YAML front matter: %w", err)
+		}
+	} else if _, err := toml.Decode(string(raw), &meta); err != nil {
+		return nil, nil, fmt.Errorf("invalid TOML front matter: %w", err)
+	}
+
+	return meta, body, nil
+}
+
+// cutLine splits data after the first line, ok is false when there is no newline
+func cutLine(data []byte) (line, rest []byte, ok bool) {
+	i := bytes.IndexByte(data, '\n')
+	if i < 0 {
+		return data, nil, false
+	}
+	return data[:i], data[i+1:], true
+}
+
+// metaTitle returns the title front matter key if it is set
+func metaTitle(meta map[string]interface{}) string {
+	title, ok := meta["title"].(string)
+	if !ok {
+		return ""
+	}
+	return title
+}
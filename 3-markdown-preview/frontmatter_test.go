@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSplitFrontMatter(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		expMeta map[string]interface{}
+		expBody string
+		expErr  bool
+	}{
+		{
+			name:    "YAML",
+			input:   "---\ntitle: Release Notes\nauthor: Jane\ntags: [go, cli]\n---\n# Body\n",
+			expMeta: map[string]interface{}{"title": "Release Notes", "author": "Jane", "tags": []interface{}{"go", "cli"}},
+			expBody: "# Body\n",
+		},
+		{
+			name:    "TOML",
+			input:   "+++\ntitle = \"Release Notes\"\nversion = 2\n+++\n# Body\n",
+			expMeta: map[string]interface{}{"title": "Release Notes", "version": int64(2)},
+			expBody: "# Body\n",
+		},
+		{
+			name:    "WindowsLineEndings",
+			input:   "---\r\ntitle: Notes\r\n---\r\nBody",
+			expMeta: map[string]interface{}{"title": "Notes"},
+			expBody: "Body",
+		},
+		{
+			name:    "NoFrontMatter",
+			input:   "# Body\n---\n",
+			expBody: "# Body\n---\n",
+		},
+		{
+			name:    "NotClosed",
+			input:   "---\ntitle: Notes\n# Body\n",
+			expBody: "---\ntitle: Notes\n# Body\n",
+		},
+		{
+			name:    "HorizontalRule",
+			input:   "---\n\nSome intro text\n\n# Title\n",
+			expBody: "---\n\nSome intro text\n\n# Title\n",
+		},
+		{
+			name:    "HorizontalRules",
+			input:   "---\n\nSome intro text\n\n---\n\n# Doc\n",
+			expBody: "---\n\nSome intro text\n\n---\n\n# Doc\n",
+		},
+		{
+			name:    "YAMLList",
+			input:   "---\n- one\n- two\n---\n# Doc\n",
+			expBody: "---\n- one\n- two\n---\n# Doc\n",
+		},
+		{
+			name:   "InvalidYAML",
+			input:  "---\ntitle: [Notes\n---\n",
+			expErr: true,
+		},
+		{
+			name:   "InvalidTOML",
+			input:  "+++\ntitle = Notes\n+++\n",
+			expErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			meta, body, err := splitFrontMatter([]byte(tc.input))
+			if tc.expErr {
+				if err == nil {
+					t.Fatal("Expected error, got nil instead")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if fmt.Sprint(meta) != fmt.Sprint(tc.expMeta) && !(len(meta) == 0 && len(tc.expMeta) == 0) {
+				t.Errorf("Got meta %v, want %v", meta, tc.expMeta)
+			}
+			if string(body) != tc.expBody {
+				t.Errorf("Got body %q, want %q", body, tc.expBody)
+			}
+		})
+	}
+}
+
+func TestFrontMatterTemplate(t *testing.T) {
+	tFname := writeTemplate(t, "{{ .Title }}|{{ .Meta.author }}|{{ .Body }}")
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"TitleFromFrontMatter", "---\ntitle: Notes\nauthor: Jane\n---\n# Heading\n", "Notes|Jane|<h1 id=\"heading\">Heading</h1>\n"},
+		{"TitleFromFirstH1", "---\nauthor: Jane\n---\n## Sub\n\n# Heading\n", "Heading|Jane|<h2 id=\"sub\">Sub</h2>\n\n<h1 id=\"heading\">Heading</h1>\n"},
+		{"DefaultTitle", "text\n", "Markdown Preview Tool||<p>text</p>\n"},
+		{"HorizontalRule", "---\n\nintro\n", "Markdown Preview Tool||<hr/>\n\n<p>intro</p>\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := parseContent([]byte(tc.input), "test.md", config{template: tFname})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(result) != tc.expected {
+				t.Errorf("Got %q, want %q", result, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/kyokomi/emoji/v2"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/russross/blackfriday/v2"
+)
+
+// markdownExtensions enables GitHub-flavored markdown: tables, strikethrough,
+// autolinked URLs and footnotes, plus heading IDs for anchors
+const markdownExtensions = blackfriday.CommonExtensions |
+	blackfriday.AutoHeadingIDs |
+	blackfriday.Footnotes
+
+// task list item markers, at the start of a list item
+var (
+	taskOpen = []byte("[ ] ")
+	taskDone = [][]byte{[]byte("[x] "), []byte("[X] ")}
+)
+
+// emojiRe matches :shortcode: emoji
+var emojiRe = regexp.MustCompile(`:[a-z0-9_+\-]+:`)
+
+// gfmTransform adds the GFM features blackfriday doesn't support to doc:
+// task list checkboxes and :emoji: shortcodes
+func gfmTransform(doc *blackfriday.Node) {
+	codes := emoji.CodeMap()
+
+	doc.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		if !entering {
+			return blackfriday.GoToNext
+		}
+
+		switch node.Type {
+		case blackfriday.Item:
+			taskItem(node)
+		case blackfriday.Text:
+			node.Literal = emojiRe.ReplaceAllFunc(node.Literal, func(code []byte) []byte {
+				if e, ok := codes[string(code)]; ok {
+					return []byte(e)
+				}
+				return code
+			})
+		}
+		return blackfriday.GoToNext
+	})
+}
+
+// taskItem turns a list item starting with [ ] or [x] into a checkbox
+func taskItem(item *blackfriday.Node) {
+	p := item.FirstChild
+	if p == nil || p.Type != blackfriday.Paragraph {
+		return
+	}
+	text := p.FirstChild
+	if text == nil || text.Type != blackfriday.Text {
+		return
+	}
+
+	checkbox := `<input type="checkbox" disabled="">`
+	switch {
+	case bytes.HasPrefix(text.Literal, taskOpen):
+	case bytes.HasPrefix(text.Literal, taskDone[0]), bytes.HasPrefix(text.Literal, taskDone[1]):
+		checkbox = `<input type="checkbox" checked="" disabled="">`
+	default:
+		return
+	}
+
+	text.Literal = text.Literal[len(taskOpen):]
+
+	box := blackfriday.NewNode(blackfriday.HTMLSpan)
+	box.Literal = []byte(checkbox + " ")
+	text.InsertBefore(box)
+}
+
+// allowTaskLists lets policy keep task list checkboxes
+func allowTaskLists(p *bluemonday.Policy) *bluemonday.Policy {
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+const (
+	gfmInputFile  = "testdata/gfm.md"
+	gfmGoldenFile = "testdata/gfm.md.html"
+)
+
+func TestGFM(t *testing.T) {
+	input, err := os.ReadFile(gfmInputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := parseContent(input, gfmInputFile, config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := os.ReadFile(gfmGoldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(expected, result) {
+		t.Logf("golden:\n%s\n", expected)
+		t.Logf("result:\n%s\n", result)
+		t.Error("Result content does not match golden file")
+	}
+}
+
+func TestGFMTransform(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"TaskOpen", "- [ ] todo\n", `<li><input type="checkbox" disabled=""> todo</li>`},
+		{"TaskDoneUpper", "- [X] done\n", `<li><input type="checkbox" checked="" disabled=""> done</li>`},
+		{"NotATask", "- [link] text\n", `<li>[link] text</li>`},
+		{"Emoji", "hi :wave:\n", `<p>hi 👋</p>`},
+		{"UnknownEmoji", "at 12:30:45 :notanemoji:\n", `<p>at 12:30:45 :notanemoji:</p>`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := parseContent([]byte(tc.input), "gfm.md", config{template: writeTemplate(t, "{{ .Body }}")})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(string(result), tc.expected) {
+				t.Errorf("Expected %q in %q", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestTaskListStrictPolicy(t *testing.T) {
+	result, err := parseContent([]byte("- [x] done\n"), "gfm.md", config{template: writeTemplate(t, "{{ .Body }}"), policy: policyStrict})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(result), "<input") {
+		t.Errorf("Expected checkbox to be removed by strict policy, got %q", result)
+	}
+}
@@ -3,12 +3,17 @@ module github.com/karanbirsingh7/pclaig/mdp
 go 1.19
 
 require (
+	github.com/BurntSushi/toml v1.2.1
+	github.com/alecthomas/chroma/v2 v2.14.0
+	github.com/kyokomi/emoji/v2 v2.2.13
 	github.com/microcosm-cc/bluemonday v1.0.21
 	github.com/russross/blackfriday/v2 v2.1.0
+	golang.org/x/net v0.0.0-20221002022538-bcab6841153b
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/aymerick/douceur v0.2.0 // indirect
+	github.com/dlclark/regexp2 v1.11.0 // indirect
 	github.com/gorilla/css v1.0.0 // indirect
-	golang.org/x/net v0.0.0-20221002022538-bcab6841153b // indirect
 )
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/russross/blackfriday/v2"
+)
+
+// defaultTheme is the color theme used when none is selected
+const defaultTheme = "github"
+
+// highlightRenderer renders fenced code blocks with a language tag as
+// highlighted HTML, everything else is rendered by blackfriday
+type highlightRenderer struct {
+	*blackfriday.HTMLRenderer
+
+	formatter *chromahtml.Formatter
+	style     *chroma.Style
+
+	// set when at least one block was highlighted, so CSS is required
+	highlighted bool
+}
+
+// newHighlightRenderer - constructor for highlightRenderer, theme is a chroma style name
+func newHighlightRenderer(theme string) (*highlightRenderer, error) {
+	if theme == "" {
+		theme = defaultTheme
+	}
+
+	style, ok := styles.Registry[theme]
+	if !ok {
+		return nil, fmt.Errorf("unknown theme %q, available themes: %s", theme, strings.Join(styles.Names(), ", "))
+	}
+
+	return &highlightRenderer{
+		HTMLRenderer: blackfriday.NewHTMLRenderer(blackfriday.HTMLRendererParameters{
+			Flags: blackfriday.CommonHTMLFlags,
+		}),
+		// classes instead of inline styles, sanitizer strips style attributes
+		formatter: chromahtml.New(chromahtml.WithClasses(true)),
+		style:     style,
+	}, nil
+}
+
+// RenderNode highlights code blocks for known languages
+func (r *highlightRenderer) RenderNode(w io.Writer, node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+	if node.Type != blackfriday.CodeBlock || len(node.Info) == 0 {
+		return r.HTMLRenderer.RenderNode(w, node, entering)
+	}
+
+	lexer := lexers.Get(strings.Fields(string(node.Info))[0])
+	if lexer == nil {
+		return r.HTMLRenderer.RenderNode(w, node, entering)
+	}
+
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, string(node.Literal))
+	if err != nil {
+		return r.HTMLRenderer.RenderNode(w, node, entering)
+	}
+
+	// format into a buffer first, so a failure falls back to a plain block
+	var buf bytes.Buffer
+	if err := r.formatter.Format(&buf, r.style, iterator); err != nil {
+		return r.HTMLRenderer.RenderNode(w, node, entering)
+	}
+
+	w.Write(buf.Bytes())
+	r.highlighted = true
+	return blackfriday.GoToNext
+}
+
+// css returns style definitions for the theme, empty if nothing was highlighted
+func (r *highlightRenderer) css() (string, error) {
+	if !r.highlighted {
+		return "", nil
+	}
+
+	var buf bytes.Buffer
+	if err := r.formatter.WriteCSS(&buf, r.style); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// highlightClasses matches class attributes generated by the highlighter
+var highlightClasses = func() *regexp.Regexp {
+	names := []string{"chroma", "bg"}
+	for _, c := range chroma.StandardTypes {
+		if c != "" {
+			names = append(names, regexp.QuoteMeta(c))
+		}
+	}
+	sort.Strings(names)
+
+	// language-* is allowed by the UGC policy on code and must be kept
+	return regexp.MustCompile(`^(` + strings.Join(names, "|") + `|language-[a-zA-Z0-9]+)$`)
+}()
+
+// allowHighlight lets policy keep highlighter classes
+func allowHighlight(p *bluemonday.Policy) *bluemonday.Policy {
+	p.AllowAttrs("class").Matching(highlightClasses).OnElements("pre", "code", "span")
+	return p
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	input := "```go\npackage main\n```\n\n```nosuchlang\nplain\n```\n\n<span class=\"evil\">x</span>\n"
+
+	testCases := []struct {
+		name     string
+		template string
+		expects  []string
+		rejects  []string
+	}{
+		{
+			name: "DefaultTemplate",
+			expects: []string{
+				`<pre class="chroma"><code><span class="line"><span class="cl"><span class="kn">package</span>`,
+				`<code class="language-nosuchlang">plain`,
+				"<style>/* Background */ .bg {",
+			},
+			rejects: []string{`class="evil"`},
+		},
+		{
+			name:     "TemplateWithoutCSS",
+			template: "{{ .Body }}",
+			expects:  []string{"<style>/* Background */ .bg {"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := config{}
+			if tc.template != "" {
+				cfg.template = writeTemplate(t, tc.template)
+			}
+
+			result, err := parseContent([]byte(input), "test.md", cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, exp := range tc.expects {
+				if !strings.Contains(string(result), exp) {
+					t.Errorf("Expected %q in %q", exp, result)
+				}
+			}
+			for _, rej := range tc.rejects {
+				if strings.Contains(string(result), rej) {
+					t.Errorf("Unexpected %q in %q", rej, result)
+				}
+			}
+		})
+	}
+}
+
+func TestHighlightTheme(t *testing.T) {
+	input := []byte("```go\npackage main\n```\n")
+
+	dark, err := parseContent(input, "test.md", config{theme: "monokai"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	light, err := parseContent(input, "test.md", config{theme: "github"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(dark) == string(light) {
+		t.Errorf("Expected different styles for different themes")
+	}
+
+	if _, err := parseContent(input, "test.md", config{theme: "no-such-theme"}); err == nil {
+		t.Errorf("Expected error for unknown theme, got nil instead")
+	}
+}
+
+func TestNoHighlightNoCSS(t *testing.T) {
+	result, err := parseContent([]byte("```\nplain\n```\n"), "test.md", config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(result), "<style>") {
+		t.Errorf("Expected no styles without highlighted blocks, got %q", result)
+	}
+}
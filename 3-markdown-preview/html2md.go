@@ -0,0 +1,530 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	// spaceRe matches whitespace runs, collapsed like browsers do
+	spaceRe = regexp.MustCompile(`\s+`)
+
+	// mdEscaper escapes characters with a meaning in markdown text
+	mdEscaper = strings.NewReplacer(
+		`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`",
+		"[", `\[`, "]", `\]`, "<", `\<`, "~", `\~`,
+	)
+
+	// blockStartRe matches text starting a line that would be read as a
+	// heading, list item or quote
+	blockStartRe = regexp.MustCompile(`^(#|[-+](?:\s|$)|>|\d+[.)](?:\s|$))`)
+
+	// destEscaper percent-encodes characters ending a link destination
+	destEscaper = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E")
+)
+
+// footnotePrefix starts anchors of footnotes rendered by blackfriday
+const footnotePrefix = "fn:"
+
+// convertHTML converts HTML read from in into markdown file outName,
+// or out when outName is empty or "-"
+func convertHTML(in io.Reader, out io.Writer, outName string) error {
+	md, err := htmlToMarkdown(in)
+	if err != nil {
+		return err
+	}
+
+	if outName == "" || outName == "-" {
+		_, err := out.Write(md)
+		return err
+	}
+	return os.WriteFile(outName, md, 0644)
+}
+
+// htmlToMarkdown converts the body of an HTML document, or an HTML snippet,
+// into markdown. Headings, paragraphs, lists, links, images, code, tables,
+// quotes and emphasis are converted, other elements are replaced by their content
+func htmlToMarkdown(r io.Reader) ([]byte, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	body := findElement(doc, atom.Body)
+	if body == nil {
+		return nil, fmt.Errorf("no HTML body found")
+	}
+
+	md := strings.Join(blocks(body), "\n\n")
+	if md == "" {
+		return []byte{}, nil
+	}
+	return []byte(md + "\n"), nil
+}
+
+// findElement returns the first element a in the tree of n
+func findElement(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// blocks converts children of n into markdown blocks, runs of inline
+// content between block elements become paragraphs
+func blocks(n *html.Node) []string {
+	out := []string{}
+	var para strings.Builder
+
+	flush := func() {
+		if p := paragraph(para.String()); p != "" {
+			out = append(out, p)
+		}
+		para.Reset()
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if !isBlock(c) {
+			para.WriteString(inline(c))
+			continue
+		}
+
+		flush()
+		if b := block(c); b != "" {
+			out = append(out, b)
+		}
+	}
+	flush()
+
+	return out
+}
+
+// isBlock checks if n is an element converted into its own markdown block
+func isBlock(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+
+	switch n.DataAtom {
+	case atom.P, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Ul, atom.Ol, atom.Pre, atom.Blockquote, atom.Hr, atom.Table,
+		atom.Div, atom.Section, atom.Article, atom.Main, atom.Header,
+		atom.Footer, atom.Nav, atom.Aside, atom.Figure, atom.Dl:
+		return true
+	}
+	return false
+}
+
+// block converts a block element into markdown
+func block(n *html.Node) string {
+	switch n.DataAtom {
+	case atom.P:
+		return paragraph(inlineChildren(n))
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		return strings.Repeat("#", level) + " " + strings.ReplaceAll(cleanInline(inlineChildren(n)), "\n", " ")
+	case atom.Ul, atom.Ol:
+		if isFootnotes(n) {
+			return footnotes(n)
+		}
+		return list(n)
+	case atom.Pre:
+		return codeBlock(n)
+	case atom.Blockquote:
+		return prefixLines(strings.Join(blocks(n), "\n\n"), "> ", ">")
+	case atom.Hr:
+		// blackfriday separates footnotes with a rule, they get their own syntax
+		if next := nextElement(n); next != nil && isFootnotes(next) {
+			return ""
+		}
+		return "---"
+	case atom.Table:
+		return table(n)
+	}
+
+	// containers such as div keep their content
+	return strings.Join(blocks(n), "\n\n")
+}
+
+// inlineChildren converts children of n into inline markdown
+func inlineChildren(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(inline(c))
+	}
+	return b.String()
+}
+
+// inline converts n into inline markdown
+func inline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return mdEscaper.Replace(spaceRe.ReplaceAllString(n.Data, " "))
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Strong, atom.B:
+		return wrap(inlineChildren(n), "**")
+	case atom.Em, atom.I:
+		return wrap(inlineChildren(n), "*")
+	case atom.Del, atom.S, atom.Strike:
+		return wrap(inlineChildren(n), "~~")
+	case atom.Code:
+		return codeSpan(textContent(n))
+	case atom.Br:
+		return "  \n"
+	case atom.A:
+		return link(n)
+	case atom.Img:
+		return "![" + mdEscaper.Replace(attr(n, "alt")) + "](" + destEscaper.Replace(attr(n, "src")) + title(n) + ")"
+	case atom.Input:
+		if attr(n, "type") != "checkbox" {
+			return ""
+		}
+		box := "[ ]"
+		if hasAttr(n, "checked") {
+			box = "[x]"
+		}
+		// task text must be separated from the box, once
+		if next := n.NextSibling; next != nil && next.Type == html.TextNode && strings.HasPrefix(spaceRe.ReplaceAllString(next.Data, " "), " ") {
+			return box
+		}
+		return box + " "
+	case atom.Sup:
+		// footnote reference, as rendered by blackfriday
+		if a := findElement(n, atom.A); a != nil && strings.HasPrefix(attr(a, "href"), "#"+footnotePrefix) {
+			return "[^" + strings.TrimPrefix(attr(a, "href"), "#"+footnotePrefix) + "]"
+		}
+	case atom.Script, atom.Style, atom.Head, atom.Title:
+		return ""
+	}
+
+	if isBlock(n) {
+		return " " + block(n) + " "
+	}
+	return inlineChildren(n)
+}
+
+// wrap surrounds text with an emphasis marker, keeping outer spaces outside
+func wrap(text, marker string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+
+	lead := text[:strings.Index(text, trimmed)]
+	trail := text[len(lead)+len(trimmed):]
+	return lead + marker + trimmed + marker + trail
+}
+
+// codeSpan quotes code with enough backticks to hold the ones it contains
+func codeSpan(code string) string {
+	fence := strings.Repeat("`", longestRun(code, '`')+1)
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		return fence + " " + code + " " + fence
+	}
+	return fence + code + fence
+}
+
+// link converts an anchor, links showing their own URL become autolinks
+func link(n *html.Node) string {
+	href := attr(n, "href")
+	text := cleanInline(inlineChildren(n))
+	if href == "" {
+		return text
+	}
+
+	if textContent(n) == href && strings.Contains(href, "://") && title(n) == "" {
+		return "<" + href + ">"
+	}
+	return "[" + text + "](" + destEscaper.Replace(href) + title(n) + ")"
+}
+
+// title returns the markdown title of a link or image, with a leading space
+func title(n *html.Node) string {
+	t := attr(n, "title")
+	if t == "" {
+		return ""
+	}
+	return " " + strconv.Quote(t)
+}
+
+// codeBlock converts a pre element into a fenced code block, the language
+// comes from a language-* class, as rendered by blackfriday
+func codeBlock(n *html.Node) string {
+	lang := ""
+	for _, el := range []*html.Node{n, findElement(n, atom.Code)} {
+		if el == nil {
+			continue
+		}
+		for _, class := range strings.Fields(attr(el, "class")) {
+			if strings.HasPrefix(class, "language-") {
+				lang = strings.TrimPrefix(class, "language-")
+			}
+		}
+	}
+
+	code := strings.TrimSuffix(textContent(n), "\n")
+	fence := strings.Repeat("`", longestRun(code, '`')+1)
+	if len(fence) < 3 {
+		fence = "```"
+	}
+	return fence + lang + "\n" + code + "\n" + fence
+}
+
+// list converts ul and ol elements, nested lists are indented under their item
+func list(n *html.Node) string {
+	items := []string{}
+	loose := false
+
+	num := 1
+	if start, err := strconv.Atoi(attr(n, "start")); err == nil {
+		num = start
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.DataAtom != atom.Li {
+			continue
+		}
+
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = strconv.Itoa(num) + ". "
+			num++
+		}
+
+		// items with paragraphs are separated by blank lines
+		sep := "\n"
+		if findElement(c, atom.P) != nil {
+			sep = "\n\n"
+			loose = true
+		}
+
+		content := strings.Join(blocks(c), sep)
+		items = append(items, marker+indent(content, len(marker)))
+	}
+
+	if loose {
+		return strings.Join(items, "\n\n")
+	}
+	return strings.Join(items, "\n")
+}
+
+// isFootnotes checks if n is a list of footnotes rendered by blackfriday
+func isFootnotes(n *html.Node) bool {
+	if n.DataAtom != atom.Ol {
+		return false
+	}
+
+	found := false
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if c.DataAtom != atom.Li || !strings.HasPrefix(attr(c, "id"), footnotePrefix) {
+			return false
+		}
+		found = true
+	}
+	return found
+}
+
+// footnotes converts a footnotes list into footnote definitions
+func footnotes(n *html.Node) string {
+	defs := []string{}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		label := "[^" + strings.TrimPrefix(attr(c, "id"), footnotePrefix) + "]: "
+		defs = append(defs, label+indent(strings.Join(blocks(c), "\n\n"), 4))
+	}
+	return strings.Join(defs, "\n")
+}
+
+// table converts a table into a GitHub-flavored markdown table
+func table(n *html.Node) string {
+	rows := [][]string{}
+	aligns := []string{}
+
+	var walk func(*html.Node)
+	walk = func(el *html.Node) {
+		for c := el.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			if c.DataAtom != atom.Tr {
+				walk(c)
+				continue
+			}
+
+			row := []string{}
+			for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+				if cell.Type != html.ElementNode || (cell.DataAtom != atom.Th && cell.DataAtom != atom.Td) {
+					continue
+				}
+				text := strings.ReplaceAll(cleanInline(inlineChildren(cell)), "\n", " ")
+				row = append(row, strings.ReplaceAll(text, "|", `\|`))
+
+				// alignment comes from the header row
+				if len(rows) == 0 {
+					aligns = append(aligns, attr(cell, "align"))
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	walk(n)
+
+	if len(rows) == 0 {
+		return ""
+	}
+
+	delims := make([]string, len(aligns))
+	for i, a := range aligns {
+		switch a {
+		case "left":
+			delims[i] = ":---"
+		case "right":
+			delims[i] = "---:"
+		case "center":
+			delims[i] = ":---:"
+		default:
+			delims[i] = "---"
+		}
+	}
+
+	lines := []string{tableRow(rows[0]), tableRow(delims)}
+	for _, r := range rows[1:] {
+		// rows must have as many cells as the header
+		for len(r) < len(aligns) {
+			r = append(r, "")
+		}
+		lines = append(lines, tableRow(r[:len(aligns)]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// tableRow formats cells as a table row
+func tableRow(cells []string) string {
+	return "| " + strings.Join(cells, " | ") + " |"
+}
+
+// cleanInline trims inline markdown, keeping hard line breaks
+func cleanInline(s string) string {
+	lines := strings.Split(s, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimLeft(lines[i], " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// paragraph cleans inline markdown of a paragraph, escaping lines that
+// would start a block such as a heading or a list item
+func paragraph(s string) string {
+	lines := strings.Split(cleanInline(s), "\n")
+	for i, l := range lines {
+		if m := blockStartRe.FindString(l); m != "" {
+			// escape the marker character, after digits of an ordered list
+			k := len(m) - len(strings.TrimLeft(m, "0123456789"))
+			lines[i] = l[:k] + `\` + l[k:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// prefixLines adds prefix to each line of s, empty lines get emptyPrefix
+func prefixLines(s, prefix, emptyPrefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if l == "" {
+			lines[i] = emptyPrefix
+			continue
+		}
+		lines[i] = prefix + l
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indent indents lines of s after the first one, to continue a list item
+func indent(s string, width int) string {
+	first, rest, ok := strings.Cut(s, "\n")
+	if !ok {
+		return s
+	}
+	return first + "\n" + prefixLines(rest, strings.Repeat(" ", width), "")
+}
+
+// textContent returns the raw text of n and its children
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
+
+// nextElement returns the next sibling element of n
+func nextElement(n *html.Node) *html.Node {
+	for c := n.NextSibling; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode {
+			return c
+		}
+	}
+	return nil
+}
+
+// attr returns the value of attribute key of n
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// hasAttr checks if n has attribute key, even without a value
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// longestRun returns the longest run of c in s
+func longestRun(s string, c byte) int {
+	longest, run := 0, 0
+	for i := 0; i < len(s); i++ {
+		if s[i] != c {
+			run = 0
+			continue
+		}
+		run++
+		if run > longest {
+			longest = run
+		}
+	}
+	return longest
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHTMLToMarkdown(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Headings", "<h1>Title</h1><h3>Sub <em>title</em></h3>", "# Title\n\n### Sub *title*\n"},
+		{"Emphasis", "<p>a <strong>bold </strong>and<em> em</em> <del>gone</del></p>", "a **bold** and *em* ~~gone~~\n"},
+		{"Escape", "<p>2 * 3 = x_y [ok] &lt;b&gt;</p>", "2 \\* 3 = x\\_y \\[ok\\] \\<b>\n"},
+		{"Links", `<p><a href="https://example.com" title="Ex">site</a> <a href="https://go.dev">https://go.dev</a> <img src="a.png" alt="pic"></p>`,
+			"[site](https://example.com \"Ex\") <https://go.dev> ![pic](a.png)\n"},
+		{"EscapeBlockStart", "<p># not a heading</p><p>1. not a list<br>2) nor this<br>&gt; nor a quote</p><p>-1 and #tag</p><ul><li>- x</li><li>+ y</li></ul>",
+			"\\# not a heading\n\n1\\. not a list  \n2\\) nor this  \n\\> nor a quote\n\n-1 and #tag\n\n- \\- x\n- \\+ y\n"},
+		{"EscapeDestination", `<p><a href="a b(c).html">x</a> <img src="my pic.png" alt="p"></p>`, "[x](a%20b%28c%29.html) ![p](my%20pic.png)\n"},
+		{"LineBreak", "<p>one<br>\n two</p>", "one  \ntwo\n"},
+		{"InlineCode", "<p>run <code>go test</code> and <code>a`b</code></p>", "run `go test` and ``a`b``\n"},
+		{"CodeBlock", "<pre><code class=\"language-go\">func main() {\n\t_ = 1 * 2\n}\n</code></pre>", "```go\nfunc main() {\n\t_ = 1 * 2\n}\n```\n"},
+		{"NestedList", "<ul><li>one<ul><li>nested</li></ul></li><li>two</li></ul>", "- one\n  - nested\n- two\n"},
+		{"OrderedList", `<ol start="3"><li><p>three</p></li><li><p>four</p></li></ol>`, "3. three\n\n4. four\n"},
+		{"Quote", "<blockquote><p>quoted</p><p>more</p></blockquote>", "> quoted\n>\n> more\n"},
+		{"Table", `<table><thead><tr><th>A</th><th align="center">B</th></tr></thead><tbody><tr><td>1|2</td><td>3</td></tr></tbody></table>`,
+			"| A | B |\n| --- | :---: |\n| 1\\|2 | 3 |\n"},
+		{"Wiki", `<html><head><title>Page</title><style>p {}</style></head><body><div class="content"><p>Hello</p><hr><span>world</span></div><script>x()</script></body></html>`,
+			"Hello\n\n---\n\nworld\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := htmlToMarkdown(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(result) != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, result)
+			}
+		})
+	}
+}
+
+// TestHTMLToMarkdownRoundTrip renders testdata into HTML, converts it back
+// into markdown and renders it again, both HTML versions must be the same
+func TestHTMLToMarkdownRoundTrip(t *testing.T) {
+	for _, f := range []string{inputFile, gfmInputFile} {
+		t.Run(filepath.Base(f), func(t *testing.T) {
+			input, err := os.ReadFile(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := config{template: writeTemplate(t, "{{ .Body }}")}
+
+			first, err := parseContent(input, f, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			md, err := htmlToMarkdown(bytes.NewReader(first))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			second, err := parseContent(md, f, cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(first, second) {
+				t.Logf("markdown:\n%s\n", md)
+				t.Errorf("Expected:\n%s\nGot:\n%s", first, second)
+			}
+		})
+	}
+}
+
+func TestConvertHTML(t *testing.T) {
+	outName := filepath.Join(t.TempDir(), "out.md")
+
+	var out bytes.Buffer
+	if err := convertHTML(strings.NewReader("<h2>Notes</h2>"), &out, outName); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected no output, got %q", out.String())
+	}
+
+	result, err := os.ReadFile(outName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(result) != "## Notes\n" {
+		t.Errorf("Expected %q, got %q", "## Notes\n", result)
+	}
+}
@@ -9,10 +9,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
-	"time"
+	"strings"
 
-	"github.com/microcosm-cc/bluemonday"
 	"github.com/russross/blackfriday/v2"
 )
 
@@ -21,7 +19,7 @@ const (
 <html>
 	<head>
 	<meta http-equiv="content-type" content="text/html; charset=utf-8">
-	<title>{{ .Title }}</title> 
+	<title>{{ .Title }}</title> {{ with .CSS }}<style>{{ . }}</style>{{ end }}
 	</head>
 	<p>file: {{ .Filename }}</p>
 	<body>{{ .Body }}</body>
@@ -33,76 +31,277 @@ type content struct {
 	Title    string
 	Body     template.HTML
 	Filename string
+	TOC      template.HTML
+
+	// styles for highlighted code blocks
+	CSS template.CSS
+
+	// front matter keys, such as .Meta.author
+	Meta map[string]interface{}
+
+	// rendered files, in order
+	Files []docFile
+
+	// word count, reading time and other statistics
+	Stats docStats
+}
+
+// config type represents the options used to render markdown
+type config struct {
+	// template file, default template is used when empty
+	template string
+
+	// template files defining blocks used by the template
+	partials []string
+
+	// built-in page theme the template extends, see layoutNames
+	layout string
+
+	// inject table of contents when template doesn't use .TOC
+	toc bool
+
+	// color theme for highlighted code blocks
+	theme string
+
+	// sanitization policy: strict, ugc, none or a policy file
+	policy string
+
+	// inline local images and stylesheets, so HTML is a single file
+	standalone bool
+
+	// commands rendering fenced blocks into SVG diagrams, by language
+	hooks hooks
+
+	// directory caching rendered diagrams, caching is disabled when empty
+	cacheDir string
+
+	// keep preview file instead of removing it once browser loaded it
+	keep bool
 }
 
 func main() {
 
-	filename := flag.String("file", "", "Markdown file to preview/process")
+	filename := flag.String("file", "", "Markdown file to preview/process, more files to combine can follow as arguments, reads STDIN if not provided")
+	outName := flag.String("out", "", "write HTML to this file instead of previewing, - for STDOUT")
 	skipPreview := flag.Bool("skip-preview", false, "skip previewing html file in browser")
-	templateFilename := flag.String("template", "", "Template file to use for header and footer")
+	templateFilename := flag.String("template", "", "Template file to use for header and footer, followed by comma separated partials")
+	layout := flag.String("layout", "", "built-in page theme: "+strings.Join(layoutNames(), ", "))
+	serveMode := flag.Bool("serve", false, "serve preview over HTTP, reloading browser on file changes")
+	addr := flag.String("addr", "localhost:3000", "address to listen on in serve mode")
+	dir := flag.String("dir", "", "directory of markdown files to convert into a static site")
+	outDir := flag.String("outdir", "site", "output directory for static site")
+	toc := flag.Bool("toc", false, "add table of contents when template doesn't include .TOC")
+	theme := flag.String("theme", defaultTheme, "color theme for code blocks syntax highlighting")
+	policy := flag.String("policy", policyUGC, "HTML sanitization policy: strict, ugc, none or a policy file")
+	standalone := flag.Bool("standalone", false, "inline local images and stylesheets into a self-contained HTML file")
+	keep := flag.Bool("keep", false, "keep the preview file instead of removing it once the browser loads it")
+	showStats := flag.Bool("stats", false, "print word count, headings, links and reading time as JSON instead of rendering")
+	toMarkdown := flag.Bool("html2md", false, "convert HTML into markdown, written to STDOUT or -out")
+	check := flag.Bool("check", false, "check for broken links, missing images, duplicate anchors and unclosed fences without generating HTML")
+	diagramHooks := hooks{}
+	flag.Var(diagramHooks, "hook", "render fenced blocks of a language into SVG with a command, as lang=command, e.g. dot=\"dot -Tsvg\"")
 	flag.Parse()
 
-	// if flag is not provided, exit
-	if *filename == "" {
+	cfg := config{
+		layout:     *layout,
+		toc:        *toc,
+		theme:      *theme,
+		policy:     *policy,
+		standalone: *standalone,
+		hooks:      diagramHooks,
+		keep:       *keep,
+	}
+
+	// diagrams are cached across runs, skip caching if there is no cache dir
+	if dir, err := os.UserCacheDir(); err == nil {
+		cfg.cacheDir = filepath.Join(dir, "mdp", "diagrams")
+	}
+
+	if *templateFilename != "" {
+		files := strings.Split(*templateFilename, ",")
+		cfg.template, cfg.partials = files[0], files[1:]
+	}
+
+	if *dir != "" {
+		if err := buildSite(*dir, *outDir, os.Stdout, cfg); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// more files can follow as arguments, combined into one document
+	files := flag.Args()
+	if *filename != "" {
+		files = append([]string{*filename}, files...)
+	}
+
+	if *serveMode {
+		// serve mode watches a single file, so it can't use STDIN
+		if len(files) != 1 {
+			flag.Usage()
+			os.Exit(1)
+		}
+		if err := serve(files[0], *addr, os.Stdout, cfg); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(files) > 1 {
+		// html2md converts a single document
+		if *toMarkdown {
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		if *showStats {
+			srcs, err := readSources(files)
+			if err == nil {
+				err = printStats(srcs, os.Stdout)
+			}
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if *check {
+			failed := false
+			for _, f := range files {
+				if err := checkFile(f, os.Stdout); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					failed = true
+				}
+			}
+			if failed {
+				os.Exit(1)
+			}
+			return
+		}
+
+		if err := runFiles(files, os.Stdout, *outName, *skipPreview, cfg); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	var (
+		in   io.Reader = os.Stdin
+		name           = "stdin"
+	)
+
+	if len(files) == 1 {
+		f, err := os.Open(files[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		in, name = f, files[0]
+	} else if isTerminal(os.Stdin) {
+		// no file and nothing piped in, exit
 		flag.Usage()
 		os.Exit(1)
 	}
 
-	if err := run(*filename, os.Stdout, *skipPreview, *templateFilename); err != nil {
+	if *showStats {
+		input, err := io.ReadAll(in)
+		if err == nil {
+			err = printStats([]source{{name: name, input: input}}, os.Stdout)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *toMarkdown {
+		if err := convertHTML(in, os.Stdout, *outName); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *check {
+		if err := runCheck(name, in, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := run(name, in, os.Stdout, *outName, *skipPreview, cfg); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
 
-func preview(fname string) error {
-	cName := ""
-	cParams := []string{}
-
-	// define executable based on Operating System/OS
-	switch runtime.GOOS {
-	case "linux":
-		cName = "xdg-open"
-	case "darwin":
-		cName = "open"
-	case "windows":
-		cName = "cmd.exe"
-		cParams = []string{
-			"/C", "start",
-		}
-	default:
-		return fmt.Errorf("OS not supported")
+// isTerminal checks if f is an interactive terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
 	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
 
-	// append file to param slaice
-	cParams = append(cParams, fname)
+// command is a package variable so tests can mock external commands
+var command = exec.Command
 
-	// locate exec in path
-	cPath, err := exec.LookPath(cName)
+// run converts markdown read from in, filename is the name shown in templates.
+// HTML goes to outName, "-" for out, or to a temporary file to preview when empty
+func run(filename string, in io.Reader, out io.Writer, outName string, skipPreview bool, cfg config) error {
+	// read markdown
+	input, err := io.ReadAll(in)
 	if err != nil {
 		return err
 	}
 
-	// run open command to open file in browser
-	err = exec.Command(cPath, cParams...).Run()
+	// convert markdown -> html header + body + footer
+	htmlData, err := parseContent(input, filename, cfg)
+	if err != nil {
+		return err
+	}
 
-	// wait since we have another function that will delete this temporary file as soon as it in opened
-	time.Sleep(2 * time.Second)
-	return err
+	return output(htmlData, out, outName, skipPreview, cfg)
 }
 
-func run(filename io, out io.Writer, skipPreview bool, tFname string) error {
-	// read file
-	input, err := os.ReadFile(filename)
+// runFiles converts several markdown files into one document, like run
+func runFiles(filenames []string, out io.Writer, outName string, skipPreview bool, cfg config) error {
+	srcs, err := readSources(filenames)
 	if err != nil {
 		return err
 	}
 
-	// convert markdown -> html header + body + footer
-	htmlData, err := parseContent(input, tFname, filename)
+	htmlData, err := parseSources(srcs, cfg)
 	if err != nil {
 		return err
 	}
 
+	return output(htmlData, out, outName, skipPreview, cfg)
+}
+
+// output writes htmlData to outName, "-" for out, or to a temporary file to preview when empty
+func output(htmlData []byte, out io.Writer, outName string, skipPreview bool, cfg config) error {
+	switch outName {
+	case "":
+	case "-":
+		_, err := out.Write(htmlData)
+		return err
+	default:
+		if err := saveHTML(outName, htmlData); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(out, outName)
+		return err
+	}
+
 	// create a temp file and check for errors
 	temp, err := os.CreateTemp("", "mdp*.html")
 	if err != nil {
@@ -112,7 +311,7 @@ func run(filename io, out io.Writer, skipPreview bool, tFname string) error {
 	if err := temp.Close(); err != nil {
 		return err
 	}
-	outName := temp.Name()
+	outName = temp.Name()
 	fmt.Fprintln(out, outName)
 
 	if err := saveHTML(outName, htmlData); err != nil {
@@ -123,10 +322,12 @@ func run(filename io, out io.Writer, skipPreview bool, tFname string) error {
 		return nil
 	}
 
-	// remove temporary file
-	defer os.Remove(outName)
+	// remove temporary file, also when preview is interrupted
+	if !cfg.keep {
+		defer os.Remove(outName)
+	}
 
-	return preview(outName)
+	return preview(outName, cfg.keep)
 }
 
 // saveHTML writes html content to a file
@@ -135,32 +336,82 @@ func saveHTML(filename string, htmlData []byte) error {
 }
 
 // parseContent parse markdown contents and convert it into html
-func parseContent(input []byte, tFname string, filename string) ([]byte, error) {
-	output := blackfriday.Run(input)
-	body := bluemonday.UGCPolicy().SanitizeBytes(output)
+func parseContent(input []byte, filename string, cfg config) ([]byte, error) {
+	return parseSources([]source{{name: filename, input: input}}, cfg)
+}
+
+// parseSources converts markdown sources, in order, into a single html document
+func parseSources(srcs []source, cfg config) ([]byte, error) {
+	doc, meta, files, err := combine(srcs)
+	if err != nil {
+		return nil, err
+	}
+	filename := srcs[0].name
+	stats := computeStats(doc)
+
+	diagrams := renderDiagrams(doc, cfg.hooks, cfg.cacheDir)
+
+	// IDs must be unique before building TOC, so links point to right heading
+	headings := uniqueHeadings(doc)
+	toc := buildTOC(headings)
+
+	renderer, err := newHighlightRenderer(cfg.theme)
+	if err != nil {
+		return nil, err
+	}
+
+	var output bytes.Buffer
+	renderer.RenderHeader(&output, doc)
+	doc.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		return renderer.RenderNode(&output, node, entering)
+	})
+	renderer.RenderFooter(&output, doc)
+
+	policy, err := newPolicy(cfg.policy)
+	if err != nil {
+		return nil, err
+	}
+
+	body := output.Bytes()
+	if policy != nil {
+		body = policy.SanitizeBytes(body)
+	}
+	body = insertDiagrams(body, diagrams)
+
+	css, err := renderer.css()
+	if err != nil {
+		return nil, err
+	}
 
 	// create a buffer of bytes to write to file
 	var buffer bytes.Buffer
 
-	// parse the contents of the default template const into new Template
-	t, err := template.New("mdp").Parse(defaultTemplate)
+	// parse the layout, template and partials into new Template
+	t, tmpl, err := loadTemplate(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	// if provided by user, then use that template
-	if tFname != "" {
-		t, err = template.ParseFiles(tFname)
-		if err != nil {
-			return nil, err
-		}
+	// template has no place for TOC, so it goes at the top of the body
+	if cfg.toc && !strings.Contains(tmpl, ".TOC") {
+		body = append([]byte(toc), body...)
+	}
+
+	// same for code styles
+	if css != "" && !strings.Contains(tmpl, ".CSS") {
+		body = append([]byte("<style>"+css+"</style>\n"), body...)
 	}
 
 	// set new field
 	c := content{
-		Title:    "Markdown Preview Tool",
+		Title:    pageTitle(meta, headings),
 		Body:     template.HTML(body),
-		Filename: filepath.Base(filename),
+		Filename: fileNames(files),
+		TOC:      toc,
+		CSS:      template.CSS(css),
+		Meta:     meta,
+		Files:    files,
+		Stats:    stats,
 	}
 
 	// render template with our variables
@@ -168,5 +419,37 @@ func parseContent(input []byte, tFname string, filename string) ([]byte, error)
 		return nil, err
 	}
 
-	return buffer.Bytes(), nil
+	if !cfg.standalone {
+		return buffer.Bytes(), nil
+	}
+
+	// stylesheets are referenced by the template, images by the document
+	styleDir := filepath.Dir(filename)
+	if cfg.template != "" {
+		styleDir = filepath.Dir(cfg.template)
+	}
+	return inlineAssets(buffer.Bytes(), filepath.Dir(filename), styleDir)
+}
+
+// fileNames joins base names of files, as shown in templates
+func fileNames(files []docFile) string {
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// pageTitle picks title from front matter, then the first H1, then a default
+func pageTitle(meta map[string]interface{}, headings []heading) string {
+	if title := metaTitle(meta); title != "" {
+		return title
+	}
+
+	for _, h := range headings {
+		if h.level == 1 {
+			return h.text
+		}
+	}
+	return "Markdown Preview Tool"
 }
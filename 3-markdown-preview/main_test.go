@@ -2,7 +2,12 @@ package main
 
 import (
 	"bytes"
+	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -13,9 +18,55 @@ const (
 	customTemplateFile = "testdata/test1.html.tpl"
 )
 
+// mockCommand - runs TestHelperProcess instead of the real command
+func mockCommand(exe string, args ...string) *exec.Cmd {
+	cs := []string{"-test.run=TestHelperProcess", "--", exe}
+	cs = append(cs, args...)
+
+	cmd := exec.Command(os.Args[0], cs...)
+	cmd.Env = []string{"GO_WANT_HELPER_PROCESS=1"}
+	return cmd
+}
+
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	args := os.Args[3:]
+
+	// render input as SVG text, like dot -Tsvg, failing on invalid input
+	if args[0] == "dot" {
+		input, _ := io.ReadAll(os.Stdin)
+		if strings.Contains(string(input), "invalid") {
+			fmt.Fprintln(os.Stderr, "syntax error")
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stdout, "<?xml version=\"1.0\"?>\n<svg><text>%s %s</text></svg>\n", args, strings.TrimSpace(string(input)))
+		os.Exit(0)
+	}
+
+	// otherwise act as a browser, loading the page unless told not to
+	target := args[len(args)-1]
+	if strings.HasPrefix(target, "http") && args[1] != "-no-load" {
+		resp, err := http.Get(target)
+		if err != nil {
+			os.Exit(1)
+		}
+		resp.Body.Close()
+	}
+	os.Exit(0)
+}
+
 func TestRun(t *testing.T) {
+	in, err := os.Open(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer in.Close()
+
 	var mockStdOut bytes.Buffer
-	if err := run(inputFile, &mockStdOut, true, ""); err != nil {
+	if err := run(inputFile, in, &mockStdOut, "", true, config{}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -39,13 +90,57 @@ func TestRun(t *testing.T) {
 
 }
 
+func TestRunOut(t *testing.T) {
+	input, err := os.ReadFile(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outFile := filepath.Join(t.TempDir(), "out.html")
+
+	testCases := []struct {
+		name    string
+		outName string
+		expOut  string
+	}{
+		{"Stdout", "-", string(expected)},
+		{"File", outFile, outFile + "\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var mockStdOut bytes.Buffer
+			if err := run(inputFile, bytes.NewReader(input), &mockStdOut, tc.outName, false, config{}); err != nil {
+				t.Fatal(err)
+			}
+
+			if mockStdOut.String() != tc.expOut {
+				t.Errorf("Got %q, want %q", mockStdOut.String(), tc.expOut)
+			}
+		})
+	}
+
+	result, err := os.ReadFile(outFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected, result) {
+		t.Errorf("Result content doesn't match golden file")
+	}
+}
+
 func TestParseContentFromFile(t *testing.T) {
 	input, err := os.ReadFile(inputFile)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	result, err := parseContent(input, customTemplateFile, inputFile)
+	result, err := parseContent(input, inputFile, config{template: customTemplateFile})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -68,7 +163,7 @@ func TestParseContent(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	result, err := parseContent(input, "", inputFile)
+	result, err := parseContent(input, inputFile, config{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"gopkg.in/yaml.v3"
+)
+
+// built-in sanitization policies, any other name is a policy file
+const (
+	policyStrict = "strict"
+	policyUGC    = "ugc"
+	policyNone   = "none"
+)
+
+// policyFile represents a declarative sanitization policy, for example:
+//
+//	base: ugc
+//	elements: [details, summary, iframe]
+//	attributes:
+//	  - names: [src, width, height]
+//	    elements: [iframe]
+//	  - names: [class]
+//	    elements: [div]
+//	    matching: "^diagram$"
+type policyFile struct {
+	// policy to extend, strict or ugc
+	Base string `yaml:"base"`
+
+	// elements allowed even without attributes
+	Elements []string `yaml:"elements"`
+
+	Attributes []policyAttrs `yaml:"attributes"`
+}
+
+// policyAttrs allows attributes on elements, or globally when no elements are set
+type policyAttrs struct {
+	Names    []string `yaml:"names"`
+	Elements []string `yaml:"elements"`
+
+	// optional regular expression attribute values must match
+	Matching string `yaml:"matching"`
+}
+
+// newPolicy returns the sanitization policy by name, or loads it from a file.
+// a nil policy means output is not sanitized
+func newPolicy(name string) (*bluemonday.Policy, error) {
+	switch name {
+	case "", policyUGC:
+		return ugcPolicy(), nil
+	case policyStrict:
+		return bluemonday.StrictPolicy(), nil
+	case policyNone:
+		return nil, nil
+	}
+
+	return loadPolicy(name)
+}
+
+// ugcPolicy allows user generated content, along with highlighted code and task lists
+func ugcPolicy() *bluemonday.Policy {
+	return allowTaskLists(allowHighlight(bluemonday.UGCPolicy()))
+}
+
+// loadPolicy builds a policy from a YAML policy file
+func loadPolicy(filename string) (*bluemonday.Policy, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read policy: %w", err)
+	}
+
+	pf := policyFile{}
+	if err := yaml.Unmarshal(data, &pf); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", filename, err)
+	}
+
+	var p *bluemonday.Policy
+	switch pf.Base {
+	case "", policyUGC:
+		p = ugcPolicy()
+	case policyStrict:
+		p = bluemonday.StrictPolicy()
+	default:
+		return nil, fmt.Errorf("invalid policy file %s: base must be %q or %q", filename, policyStrict, policyUGC)
+	}
+
+	if len(pf.Elements) > 0 {
+		p.AllowElements(pf.Elements...)
+	}
+
+	for _, a := range pf.Attributes {
+		if len(a.Names) == 0 {
+			return nil, fmt.Errorf("invalid policy file %s: attributes without names", filename)
+		}
+
+		b := p.AllowAttrs(a.Names...)
+		if a.Matching != "" {
+			re, err := regexp.Compile(a.Matching)
+			if err != nil {
+				return nil, fmt.Errorf("invalid policy file %s: %w", filename, err)
+			}
+			b = b.Matching(re)
+		}
+
+		if len(a.Elements) == 0 {
+			b.Globally()
+			continue
+		}
+		b.OnElements(a.Elements...)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const unsafeInput = `# Title
+
+<script>alert("xss")</script>
+
+<a href="javascript:alert(1)" onclick="alert(2)">click</a>
+
+<iframe src="https://example.com/diagram"></iframe>
+
+<div class="diagram">d</div> <div class="evil">e</div>
+
+<details><summary>More</summary>hidden</details>
+`
+
+func TestPolicy(t *testing.T) {
+	testCases := []struct {
+		name    string
+		policy  string
+		expects []string
+		rejects []string
+	}{
+		{
+			name:    "DefaultUGC",
+			policy:  "",
+			expects: []string{`<h1 id="title">Title</h1>`, "<details><summary>More</summary>"},
+			rejects: []string{"<script", "alert(", "onclick", "javascript:", "<iframe", `class="diagram"`},
+		},
+		{
+			name:    "Strict",
+			policy:  policyStrict,
+			expects: []string{"Title"},
+			rejects: []string{"<h1", "<script", "alert(", "<iframe", "<details"},
+		},
+		{
+			name:    "None",
+			policy:  policyNone,
+			expects: []string{`<script>alert("xss")</script>`, `onclick="alert(2)"`, "<iframe"},
+		},
+		{
+			name:    "File",
+			policy:  "testdata/policy.yaml",
+			expects: []string{`<iframe src="https://example.com/diagram">`, `<div class="diagram">`, "<details>"},
+			rejects: []string{"<script", "alert(", "onclick", "javascript:", `class="evil"`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := config{
+				template: writeTemplate(t, "{{ .Body }}"),
+				policy:   tc.policy,
+			}
+			result, err := parseContent([]byte(unsafeInput), "test.md", cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, exp := range tc.expects {
+				if !strings.Contains(string(result), exp) {
+					t.Errorf("Expected %q in %q", exp, result)
+				}
+			}
+			for _, rej := range tc.rejects {
+				if strings.Contains(string(result), rej) {
+					t.Errorf("Unexpected %q in %q", rej, result)
+				}
+			}
+		})
+	}
+}
+
+func TestPolicyFileErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{"InvalidYAML", "elements: [iframe"},
+		{"InvalidBase", "base: none"},
+		{"AttributesWithoutNames", "attributes:\n  - elements: [div]"},
+		{"InvalidRegex", "attributes:\n  - names: [class]\n    matching: \"(\""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := newPolicy(writeTemplate(t, tc.content)); err == nil {
+				t.Errorf("Expected error, got nil instead")
+			}
+		})
+	}
+
+	if _, err := newPolicy("testdata/no-such-policy.yaml"); err == nil {
+		t.Errorf("Expected error for missing policy file, got nil instead")
+	}
+}
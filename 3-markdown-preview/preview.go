@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// previewTimeout is how long preview waits for the browser to load the page,
+// package variable so tests don't wait that long
+var previewTimeout = time.Minute
+
+// preview opens fname in a browser. Unless keep is set, the browser loads the
+// page from a one-shot local server and preview returns once it is loaded, so
+// the caller can remove the file right away. Interrupting preview returns an
+// error, so the caller still cleans up
+func preview(fname string, keep bool) error {
+	if keep {
+		name, args, err := browserCommand(fname)
+		if err != nil {
+			return err
+		}
+		return command(name, args...).Run()
+	}
+
+	htmlData, err := os.ReadFile(fname)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+
+	loaded := make(chan struct{})
+	var once sync.Once
+
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write(htmlData)
+			once.Do(func() { close(loaded) })
+		}),
+	}
+	go srv.Serve(ln)
+
+	defer func() {
+		// let the browser finish reading the page
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	}()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sig)
+
+	url := "http://" + ln.Addr().String() + "/"
+	name, args, err := browserCommand(url)
+	if err != nil {
+		return err
+	}
+
+	// browsers may run until closed, so the command isn't waited for here
+	cmd := command(name, args...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+
+	select {
+	case <-loaded:
+		return nil
+	case s := <-sig:
+		return fmt.Errorf("preview interrupted: %s", s)
+	case <-time.After(previewTimeout):
+		return fmt.Errorf("timed out waiting for browser to load %s", url)
+	}
+}
+
+// browserCommand returns the command opening target. $BROWSER is a list of
+// commands separated like $PATH, the first one found is used, and %s in it
+// is replaced by target. Otherwise the OS default opener is used
+func browserCommand(target string) (string, []string, error) {
+	if env := os.Getenv("BROWSER"); env != "" {
+		for _, entry := range strings.Split(env, string(os.PathListSeparator)) {
+			fields := strings.Fields(entry)
+			if len(fields) == 0 {
+				continue
+			}
+			if _, err := exec.LookPath(fields[0]); err != nil {
+				continue
+			}
+
+			args := []string{}
+			replaced := false
+			for _, f := range fields[1:] {
+				if strings.Contains(f, "%s") {
+					f = strings.ReplaceAll(f, "%s", target)
+					replaced = true
+				}
+				args = append(args, f)
+			}
+			if !replaced {
+				args = append(args, target)
+			}
+			return fields[0], args, nil
+		}
+		return "", nil, fmt.Errorf("no browser from $BROWSER %q found", env)
+	}
+
+	// define executable based on Operating System/OS
+	switch runtime.GOOS {
+	case "linux":
+		return "xdg-open", []string{target}, nil
+	case "darwin":
+		return "open", []string{target}, nil
+	case "windows":
+		return "cmd.exe", []string{"/C", "start", target}, nil
+	}
+	return "", nil, fmt.Errorf("OS not supported")
+}
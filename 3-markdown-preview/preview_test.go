@@ -0,0 +1,122 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBrowserCommand(t *testing.T) {
+	// test binary is an executable found by absolute path
+	exe := os.Args[0]
+
+	testCases := []struct {
+		name    string
+		browser string
+		expName string
+		expArgs []string
+		expErr  string
+	}{
+		{"Append", exe + " --new-window", exe, []string{"--new-window", "page.html"}, ""},
+		{"Placeholder", exe + " --url=%s --bg", exe, []string{"--url=page.html", "--bg"}, ""},
+		{"FirstFound", "not-a-browser-mdp" + string(os.PathListSeparator) + exe, exe, []string{"page.html"}, ""},
+		{"NotFound", "not-a-browser-mdp", "", nil, "no browser from $BROWSER"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("BROWSER", tc.browser)
+
+			name, args, err := browserCommand("page.html")
+			if tc.expErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expErr) {
+					t.Fatalf("Expected error %q, got %v", tc.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if name != tc.expName || strings.Join(args, " ") != strings.Join(tc.expArgs, " ") {
+				t.Errorf("Expected %s %v, got %s %v", tc.expName, tc.expArgs, name, args)
+			}
+		})
+	}
+}
+
+func TestPreview(t *testing.T) {
+	command = mockCommand
+	defer func() { command = exec.Command }()
+
+	oldTimeout := previewTimeout
+	previewTimeout = 2 * time.Second
+	defer func() { previewTimeout = oldTimeout }()
+
+	fname := filepath.Join(t.TempDir(), "preview.html")
+	if err := os.WriteFile(fname, []byte("<p>preview</p>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name    string
+		browser string
+		keep    bool
+		expErr  string
+	}{
+		{"Loaded", os.Args[0], false, ""},
+		{"Keep", os.Args[0], true, ""},
+		{"NotLoaded", os.Args[0] + " -no-load", false, "timed out waiting for browser"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("BROWSER", tc.browser)
+
+			err := preview(fname, tc.keep)
+			if tc.expErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expErr) {
+					t.Fatalf("Expected error %q, got %v", tc.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestRunPreviewCleanup(t *testing.T) {
+	command = mockCommand
+	defer func() { command = exec.Command }()
+	t.Setenv("BROWSER", os.Args[0])
+
+	input, err := os.ReadFile(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, keep := range []bool{false, true} {
+		var out strings.Builder
+		if err := run(inputFile, strings.NewReader(string(input)), &out, "", false, config{keep: keep}); err != nil {
+			t.Fatal(err)
+		}
+
+		fname := strings.TrimSpace(out.String())
+		_, err := os.Stat(fname)
+		if keep {
+			if err != nil {
+				t.Errorf("Expected preview file %s to be kept: %v", fname, err)
+			}
+			os.Remove(fname)
+			continue
+		}
+		if !os.IsNotExist(err) {
+			t.Errorf("Expected preview file %s to be removed, got %v", fname, err)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// reloadScript is injected in served pages, it reloads the page on server events
+const reloadScript = `<script>
+	new EventSource("/events").onmessage = function() { location.reload(); };
+</script>
+`
+
+// previewServer serves rendered markdown and notifies browsers when it changes
+type previewServer struct {
+	filename string
+	cfg      config
+
+	mu      sync.Mutex
+	clients map[chan struct{}]struct{}
+
+	// closed on shutdown so open event streams return
+	done chan struct{}
+}
+
+// newPreviewServer - constructor for previewServer
+func newPreviewServer(filename string, cfg config) *previewServer {
+	return &previewServer{
+		filename: filename,
+		cfg:      cfg,
+		clients:  map[chan struct{}]struct{}{},
+		done:     make(chan struct{}),
+	}
+}
+
+// handler returns the routes for the preview server
+func (s *previewServer) handler() http.Handler {
+	m := http.NewServeMux()
+	m.HandleFunc("/", s.pageHandler)
+	m.HandleFunc("/events", s.eventsHandler)
+	return m
+}
+
+// pageHandler renders markdown file on every request, so page is always current
+func (s *previewServer) pageHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	input, err := os.ReadFile(s.filename)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	htmlData, err := parseContent(input, s.filename, s.cfg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(injectScript(htmlData))
+}
+
+// injectScript adds reloadScript right before </body>, or at the end if missing
+func injectScript(htmlData []byte) []byte {
+	i := bytes.LastIndex(htmlData, []byte("</body>"))
+	if i < 0 {
+		return append(htmlData, reloadScript...)
+	}
+
+	out := append([]byte{}, htmlData[:i]...)
+	out = append(out, reloadScript...)
+	return append(out, htmlData[i:]...)
+}
+
+// eventsHandler streams Server-Sent Events, one reload message per change
+func (s *previewServer) eventsHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// buffered, so broadcast never blocks on a slow browser
+	ch := make(chan struct{}, 1)
+	s.mu.Lock()
+	s.clients[ch] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, ch)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-ch:
+			if _, err := fmt.Fprint(w, "data: reload\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// broadcast notifies every connected browser
+func (s *previewServer) broadcast() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.clients {
+		select {
+		case ch <- struct{}{}:
+		default: // reload already pending for this client
+		}
+	}
+}
+
+// modTimes returns modification times of the files being watched
+func (s *previewServer) modTimes() []time.Time {
+	files := []string{s.filename}
+	if s.cfg.template != "" {
+		files = append(files, s.cfg.template)
+	}
+	files = append(files, s.cfg.partials...)
+
+	times := make([]time.Time, len(files))
+	for i, f := range files {
+		// missing files, e.g. while an editor saves them, count as unchanged
+		if info, err := os.Stat(f); err == nil {
+			times[i] = info.ModTime()
+		}
+	}
+	return times
+}
+
+// watch polls markdown and template files, broadcasting when they change
+func (s *previewServer) watch(ctx context.Context, interval time.Duration) {
+	last := s.modTimes()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current := s.modTimes()
+		for i := range current {
+			if !current[i].IsZero() && !current[i].Equal(last[i]) {
+				s.broadcast()
+				break
+			}
+		}
+		last = current
+	}
+}
+
+// serve hosts the preview on addr until interrupted
+func serve(filename, addr string, out io.Writer, cfg config) error {
+	s := newPreviewServer(filename, cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.watch(ctx, 500*time.Millisecond)
+
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: s.handler(),
+	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sig)
+
+	errCh := make(chan error)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	fmt.Fprintf(out, "Serving preview on http://%s, press Ctrl+C to stop\n", addr)
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-sig:
+		close(s.done)
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
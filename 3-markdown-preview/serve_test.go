@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupServe - copies input file to a temp dir and starts a test server on it
+func setupServe(t *testing.T) (*previewServer, string, string) {
+	t.Helper()
+
+	input, err := os.ReadFile(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mdFile := filepath.Join(t.TempDir(), "test1.md")
+	if err := os.WriteFile(mdFile, input, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newPreviewServer(mdFile, config{})
+	ts := httptest.NewServer(s.handler())
+	t.Cleanup(func() {
+		close(s.done)
+		ts.Close()
+	})
+
+	return s, ts.URL, mdFile
+}
+
+func TestServePage(t *testing.T) {
+	_, url, _ := setupServe(t)
+
+	r, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Body.Close()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.StatusCode != http.StatusOK {
+		t.Fatalf("Expected %q, got %q", http.StatusText(http.StatusOK), r.Status)
+	}
+	if !strings.Contains(string(body), `<h1 id="test-markdown-file">Test Markdown File</h1>`) {
+		t.Errorf("Expected rendered markdown, got %q", body)
+	}
+	if !strings.Contains(string(body), reloadScript+"</body>") {
+		t.Errorf("Expected reload script before </body>, got %q", body)
+	}
+}
+
+func TestServeReload(t *testing.T) {
+	s, url, mdFile := setupServe(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.watch(ctx, 10*time.Millisecond)
+
+	r, err := http.Get(url + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Body.Close()
+
+	if ct := r.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected event stream, got %q", ct)
+	}
+
+	// change file after stream is open, using a later mod time so the change
+	// is detected even on file systems with coarse timestamps
+	if err := os.WriteFile(mdFile, []byte("# Changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(mdFile, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := make(chan string)
+	go func() {
+		sc := bufio.NewScanner(r.Body)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+	}()
+
+	select {
+	case line := <-lines:
+		if line != "data: reload" {
+			t.Errorf("Got %q, want %q", line, "data: reload")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for reload event")
+	}
+
+	// page now shows the new content
+	page, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer page.Body.Close()
+	body, _ := io.ReadAll(page.Body)
+	if !strings.Contains(string(body), `<h1 id="changed">Changed</h1>`) {
+		t.Errorf("Expected re-rendered markdown, got %q", body)
+	}
+}
+
+func TestInjectScript(t *testing.T) {
+	testCases := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{"WithBody", "<body>x</body></html>", "<body>x" + reloadScript + "</body></html>"},
+		{"NoBody", "<p>x</p>", "<p>x</p>" + reloadScript},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := string(injectScript([]byte(tc.html))); got != tc.expected {
+				t.Errorf("Got %q, want %q", got, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// hrefRe matches href attributes in rendered HTML
+var hrefRe = regexp.MustCompile(`href="([^"]*)"`)
+
+// buildSite converts every markdown file under srcDir into HTML under dstDir,
+// copying other files as assets and generating an index page.
+// an index.md in srcDir replaces the generated index page.
+func buildSite(srcDir, dstDir string, out io.Writer, cfg config) error {
+	srcAbs, err := filepath.Abs(srcDir)
+	if err != nil {
+		return err
+	}
+	dstAbs, err := filepath.Abs(dstDir)
+	if err != nil {
+		return err
+	}
+
+	docs := []string{}
+
+	err = filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return err
+			}
+			// don't convert our own output, or hidden dirs such as .git
+			if abs == dstAbs || (rel != "." && strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if filepath.Ext(path) != ".md" {
+			return copyFile(path, filepath.Join(dstDir, rel))
+		}
+
+		htmlRel := strings.TrimSuffix(rel, ".md") + ".html"
+		if err := convertFile(path, filepath.Join(dstDir, htmlRel), cfg); err != nil {
+			return err
+		}
+		docs = append(docs, htmlRel)
+
+		_, err = fmt.Fprintln(out, filepath.Join(dstDir, htmlRel))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// source has its own index page
+	if _, err := os.Stat(filepath.Join(srcAbs, "index.md")); err == nil {
+		return nil
+	}
+
+	indexName := filepath.Join(dstDir, "index.html")
+	if err := saveIndex(indexName, docs, cfg); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, indexName)
+	return err
+}
+
+// convertFile renders markdown file src into HTML file dst
+func convertFile(src, dst string, cfg config) error {
+	input, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	htmlData, err := parseContent(input, src, cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return saveHTML(dst, rewriteLinks(htmlData))
+}
+
+// rewriteLinks points relative links to .md documents to their .html version
+func rewriteLinks(htmlData []byte) []byte {
+	return hrefRe.ReplaceAllFunc(htmlData, func(m []byte) []byte {
+		href := string(hrefRe.FindSubmatch(m)[1])
+
+		u, err := url.Parse(href)
+		// leave absolute URLs, protocol relative and root relative paths alone
+		if err != nil || u.Scheme != "" || u.Host != "" || strings.HasPrefix(u.Path, "/") {
+			return m
+		}
+
+		// keep any #fragment or ?query after the document path
+		path, suffix := href, ""
+		if i := strings.IndexAny(href, "#?"); i >= 0 {
+			path, suffix = href[:i], href[i:]
+		}
+		if filepath.Ext(path) != ".md" {
+			return m
+		}
+
+		return []byte(`href="` + strings.TrimSuffix(path, ".md") + ".html" + suffix + `"`)
+	})
+}
+
+// saveIndex writes an index page linking to all docs, using the same template
+func saveIndex(filename string, docs []string, cfg config) error {
+	var md bytes.Buffer
+
+	fmt.Fprintln(&md, "# Index")
+	fmt.Fprintln(&md)
+	for _, d := range docs {
+		link := filepath.ToSlash(d)
+		fmt.Fprintf(&md, "* [%s](%s)\n", strings.TrimSuffix(link, ".html"), link)
+	}
+
+	htmlData, err := parseContent(md.Bytes(), "index.md", cfg)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	return saveHTML(filename, htmlData)
+}
+
+// copyFile copies asset src to dst, creating parent directories
+func copyFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, in); err != nil {
+		return err
+	}
+	return out.Close()
+}
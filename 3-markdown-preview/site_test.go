@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setupSiteDir - creates a source tree of docs and assets
+func setupSiteDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestBuildSite(t *testing.T) {
+	src := setupSiteDir(t, map[string]string{
+		"README.md":      "# Docs\n\nSee [guide](guide/setup.md#install) and [site](https://example.com/x.md).\n\n![logo](img/logo.png)\n",
+		"guide/setup.md": "# Setup\n\nBack to [readme](../README.md).\n",
+		"img/logo.png":   "not really a png",
+		".git/config":    "ignored",
+	})
+	dst := filepath.Join(t.TempDir(), "site")
+
+	var out bytes.Buffer
+	if err := buildSite(src, dst, &out, config{}); err != nil {
+		t.Fatal(err)
+	}
+
+	expOut := strings.Join([]string{
+		filepath.Join(dst, "README.html"),
+		filepath.Join(dst, "guide", "setup.html"),
+		filepath.Join(dst, "index.html"),
+	}, "\n") + "\n"
+	if out.String() != expOut {
+		t.Errorf("Got %q, want %q", out.String(), expOut)
+	}
+
+	testCases := []struct {
+		name    string
+		file    string
+		expects []string
+	}{
+		{"RelativeLinkRewritten", "README.html", []string{`href="guide/setup.html#install"`}},
+		{"AbsoluteLinkKept", "README.html", []string{`href="https://example.com/x.md"`}},
+		{"ParentLinkRewritten", "guide/setup.html", []string{`href="../README.html"`}},
+		{"AssetCopied", "img/logo.png", []string{"not really a png"}},
+		{"Index", "index.html", []string{`href="README.html"`, `href="guide/setup.html"`}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join(dst, tc.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, exp := range tc.expects {
+				if !strings.Contains(string(data), exp) {
+					t.Errorf("Expected %q in %s, got %q", exp, tc.file, data)
+				}
+			}
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(dst, ".git")); err == nil {
+		t.Errorf("Hidden directory should not be copied")
+	}
+}
+
+func TestBuildSiteOwnIndex(t *testing.T) {
+	src := setupSiteDir(t, map[string]string{
+		"index.md": "# My own index\n",
+	})
+	// output inside source dir must not be converted again
+	dst := filepath.Join(src, "site")
+
+	var out bytes.Buffer
+	if err := buildSite(src, dst, &out, config{}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dst, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "My own index") {
+		t.Errorf("Expected index.md to be used as index page, got %q", data)
+	}
+
+	// a second build must not pick up the previous output
+	out.Reset()
+	if err := buildSite(src, dst, &out, config{}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(out.String(), "\n") != 1 {
+		t.Errorf("Expected only index.html to be built, got %q", out.String())
+	}
+}
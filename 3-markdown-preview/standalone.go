@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// imgRe and linkRe match whole tags in rendered HTML
+	imgRe  = regexp.MustCompile(`(?i)<img\b[^>]*>`)
+	linkRe = regexp.MustCompile(`(?i)<link\b[^>]*>`)
+
+	// attrRe matches a single or double quoted attribute inside a tag
+	attrRe = regexp.MustCompile(`(?i)\s(src|href|rel)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+)
+
+// inlineAssets makes htmlData self-contained: local images become data URIs
+// and local stylesheets become <style> elements. Images are relative to
+// docDir, stylesheets to styleDir. Remote URLs are left alone
+func inlineAssets(htmlData []byte, docDir, styleDir string) ([]byte, error) {
+	var err error
+
+	htmlData = imgRe.ReplaceAllFunc(htmlData, func(tag []byte) []byte {
+		if err != nil {
+			return tag
+		}
+
+		loc := attrRe.FindAllSubmatchIndex(tag, -1)
+		for _, l := range loc {
+			if !strings.EqualFold(string(tag[l[2]:l[3]]), "src") {
+				continue
+			}
+
+			path, ok := localPath(attrValue(tag, l))
+			if !ok {
+				return tag
+			}
+
+			var data []byte
+			data, err = os.ReadFile(filepath.Join(docDir, path))
+			if err != nil {
+				err = fmt.Errorf("cannot inline image: %w", err)
+				return tag
+			}
+
+			src := fmt.Sprintf(` src="data:%s;base64,%s"`, mimeType(path, data), base64.StdEncoding.EncodeToString(data))
+
+			out := append([]byte{}, tag[:l[0]]...)
+			out = append(out, src...)
+			return append(out, tag[l[1]:]...)
+		}
+		return tag
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	htmlData = linkRe.ReplaceAllFunc(htmlData, func(tag []byte) []byte {
+		if err != nil {
+			return tag
+		}
+
+		var href, rel string
+		for _, l := range attrRe.FindAllSubmatchIndex(tag, -1) {
+			switch strings.ToLower(string(tag[l[2]:l[3]])) {
+			case "href":
+				href = attrValue(tag, l)
+			case "rel":
+				rel = attrValue(tag, l)
+			}
+		}
+
+		if !strings.EqualFold(strings.TrimSpace(rel), "stylesheet") {
+			return tag
+		}
+
+		path, ok := localPath(href)
+		if !ok {
+			return tag
+		}
+
+		var data []byte
+		data, err = os.ReadFile(filepath.Join(styleDir, path))
+		if err != nil {
+			err = fmt.Errorf("cannot inline stylesheet: %w", err)
+			return tag
+		}
+
+		return []byte("<style>\n" + string(data) + "\n</style>")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return htmlData, nil
+}
+
+// attrValue returns the unescaped value of the attribute matched at loc
+func attrValue(tag []byte, loc []int) string {
+	// double quoted value is group 2, single quoted group 3
+	if loc[4] >= 0 {
+		return html.UnescapeString(string(tag[loc[4]:loc[5]]))
+	}
+	return html.UnescapeString(string(tag[loc[6]:loc[7]]))
+}
+
+// localPath returns the file path of a relative URL, ok is false for
+// remote, data and root relative URLs which can't be inlined
+func localPath(ref string) (string, bool) {
+	u, err := url.Parse(ref)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" || strings.HasPrefix(u.Path, "/") {
+		return "", false
+	}
+	return filepath.FromSlash(u.Path), true
+}
+
+// mimeType guesses the media type from the file extension, then the content
+func mimeType(path string, data []byte) string {
+	if t := mime.TypeByExtension(filepath.Ext(path)); t != "" {
+		return t
+	}
+	return http.DetectContentType(data)
+}
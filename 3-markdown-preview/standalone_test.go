@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStandalone(t *testing.T) {
+	dir := t.TempDir()
+
+	// 1x1 transparent GIF
+	gif := []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00;")
+	if err := os.WriteFile(filepath.Join(dir, "pixel.gif"), gif, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := writeTemplate(t, `<link rel="stylesheet" href="style.css"><link rel="icon" href="favicon.ico">{{ .Body }}`)
+	if err := os.WriteFile(filepath.Join(filepath.Dir(tmpl), "style.css"), []byte("body { margin: 0; }"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	input := "![pixel](pixel.gif)\n\n![remote](https://example.com/logo.png)\n"
+
+	result, err := parseContent([]byte(input), filepath.Join(dir, "doc.md"), config{template: tmpl, standalone: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`<style>` + "\n" + `body { margin: 0; }` + "\n" + `</style>`,
+		`<link rel="icon" href="favicon.ico">`,
+		`src="data:image/gif;base64,R0lGODlhAQABAAAAADs="`,
+		`src="https://example.com/logo.png"`,
+	}
+	for _, exp := range expected {
+		if !strings.Contains(string(result), exp) {
+			t.Errorf("Expected %q in %q", exp, result)
+		}
+	}
+}
+
+func TestStandaloneMissingImage(t *testing.T) {
+	_, err := parseContent([]byte("![missing](missing.png)\n"), filepath.Join(t.TempDir(), "doc.md"), config{standalone: true})
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "cannot inline image") {
+		t.Errorf("Unexpected error %q", err)
+	}
+}
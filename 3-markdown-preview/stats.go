@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/russross/blackfriday/v2"
+)
+
+// docStats are document statistics, available in templates as .Stats
+type docStats struct {
+	Words    int `json:"words"`
+	Headings int `json:"headings"`
+	Links    int `json:"links"`
+
+	// estimated minutes to read the document
+	ReadingTime int `json:"reading_time"`
+}
+
+// computeStats counts words of text and inline code, headings and links
+// of doc. Code blocks and raw HTML aren't counted as words
+func computeStats(doc *blackfriday.Node) docStats {
+	stats := docStats{}
+	var text strings.Builder
+
+	doc.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		switch node.Type {
+		case blackfriday.Text, blackfriday.Code:
+			if entering {
+				text.Write(node.Literal)
+			}
+		case blackfriday.Softbreak, blackfriday.Hardbreak:
+			text.WriteByte(' ')
+		case blackfriday.Paragraph, blackfriday.Heading, blackfriday.Item, blackfriday.TableCell:
+			// words of separate blocks must not run together
+			if !entering {
+				text.WriteByte(' ')
+			}
+			if entering && node.Type == blackfriday.Heading && !node.IsTitleblock {
+				stats.Headings++
+			}
+		case blackfriday.Link:
+			if entering {
+				stats.Links++
+			}
+		}
+		return blackfriday.GoToNext
+	})
+
+	stats.Words = len(strings.Fields(text.String()))
+	stats.ReadingTime = readingMinutes(stats.Words)
+	return stats
+}
+
+// printStats writes statistics of markdown sources, as one document, as JSON
+func printStats(srcs []source, out io.Writer) error {
+	doc, _, _, err := combine(srcs)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(computeStats(doc), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = out.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestComputeStats(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected docStats
+	}{
+		{"Empty", "", docStats{ReadingTime: 1}},
+		{"Paragraphs", "one **two** three\nfour\n\nfive", docStats{Words: 5, ReadingTime: 1}},
+		{"Headings", "# Title\n\n## Sub\n\ntext", docStats{Words: 3, Headings: 2, ReadingTime: 1}},
+		{"Links", "[a link](x.md) and https://example.com", docStats{Words: 4, Links: 2, ReadingTime: 1}},
+		{"CodeBlock", "run `go test`\n\n```\nnot counted here\n```\n", docStats{Words: 3, ReadingTime: 1}},
+		{"Blocks", "- one\n- two\n\n| a | b |\n| --- | --- |\n| c | d |\n", docStats{Words: 6, ReadingTime: 1}},
+		{"ReadingTime", strings.Repeat("word ", 401), docStats{Words: 401, ReadingTime: 3}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := computeStats(parseMarkdown([]byte(tc.input)))
+			if result != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestStatsTemplate(t *testing.T) {
+	tmpl := writeTemplate(t, "{{ with .Stats }}{{ .Words }} {{ .Headings }} {{ .Links }} {{ .ReadingTime }}{{ end }}")
+
+	result, err := parseContent([]byte("# Hi\n\n[there](#hi)\n"), "stats.md", config{template: tmpl})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(result) != "2 1 1 1" {
+		t.Errorf("Expected %q, got %q", "2 1 1 1", result)
+	}
+}
+
+func TestPrintStats(t *testing.T) {
+	input, err := os.ReadFile(inputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := printStats([]source{{name: inputFile, input: input}}, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{
+  "words": 11,
+  "headings": 3,
+  "links": 1,
+  "reading_time": 1
+}
+`
+	if out.String() != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, out.String())
+	}
+}
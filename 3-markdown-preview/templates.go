@@ -0,0 +1,138 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// layouts holds the built-in page themes, base.html defines the page with
+// head, header and footer blocks, every other file defines its "theme" styles
+//
+//go:embed layouts/*.html
+var layouts embed.FS
+
+// wordsPerMinute is the reading speed used to estimate reading time
+const wordsPerMinute = 200
+
+// tagRe matches HTML tags, to count words of rendered content
+var tagRe = regexp.MustCompile(`<[^>]*>`)
+
+// templateFuncs are the helper functions available in templates
+var templateFuncs = template.FuncMap{
+	"date":        formatDate,
+	"now":         time.Now,
+	"wordCount":   wordCount,
+	"readingTime": readingTime,
+}
+
+// layoutNames returns the names of the built-in page themes
+func layoutNames() []string {
+	entries, err := layouts.ReadDir("layouts")
+	if err != nil {
+		return nil
+	}
+
+	names := []string{}
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".html")
+		if name != "base" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadTemplate parses the page template for cfg: the built-in layout if any,
+// then the template file overriding it, then the partials.
+// it also returns the combined source, to check which fields are used
+func loadTemplate(cfg config) (*template.Template, string, error) {
+	sources := []string{}
+
+	if cfg.layout != "" {
+		base, err := layouts.ReadFile("layouts/base.html")
+		if err != nil {
+			return nil, "", err
+		}
+		theme, err := layouts.ReadFile("layouts/" + cfg.layout + ".html")
+		if err != nil || cfg.layout == "base" {
+			return nil, "", fmt.Errorf("unknown layout %q, available layouts: %s", cfg.layout, strings.Join(layoutNames(), ", "))
+		}
+		sources = append(sources, string(base), string(theme))
+	}
+
+	files := []string{}
+	if cfg.template != "" {
+		files = append(files, cfg.template)
+	}
+	files = append(files, cfg.partials...)
+
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, "", err
+		}
+		sources = append(sources, string(data))
+	}
+
+	if len(sources) == 0 {
+		sources = append(sources, defaultTemplate)
+	}
+
+	// templates parsed later redefine blocks of earlier ones, a template
+	// made only of {{ define }} blocks keeps the layout page
+	t := template.New("mdp").Funcs(templateFuncs)
+	for _, src := range sources {
+		if _, err := t.Parse(src); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return t, strings.Join(sources, "\n"), nil
+}
+
+// formatDate formats a time or a date string, such as a front matter date,
+// using a Go time layout
+func formatDate(layout string, v interface{}) (string, error) {
+	switch d := v.(type) {
+	case time.Time:
+		return d.Format(layout), nil
+	case string:
+		for _, l := range []string{time.RFC3339, "2006-01-02"} {
+			if t, err := time.Parse(l, d); err == nil {
+				return t.Format(layout), nil
+			}
+		}
+		return "", fmt.Errorf("invalid date %q", d)
+	case nil:
+		return "", nil
+	}
+	return "", fmt.Errorf("invalid date %v", v)
+}
+
+// wordCount counts words of text, ignoring HTML tags
+func wordCount(v interface{}) int {
+	text := tagRe.ReplaceAllString(fmt.Sprint(v), " ")
+	return len(strings.Fields(text))
+}
+
+// readingTime estimates the minutes needed to read text
+func readingTime(v interface{}) int {
+	return readingMinutes(wordCount(v))
+}
+
+// readingMinutes estimates the minutes needed to read words, at least one
+func readingMinutes(words int) int {
+	minutes := int(math.Ceil(float64(words) / wordsPerMinute))
+	if minutes < 1 {
+		return 1
+	}
+	return minutes
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLayouts(t *testing.T) {
+	input := []byte("# Hello\n\nSome words here\n")
+
+	for _, name := range layoutNames() {
+		t.Run(name, func(t *testing.T) {
+			result, err := parseContent(input, "hello.md", config{layout: name})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := []string{
+				"<title>Hello</title>",
+				"<style>",
+				`<h1 id="hello">Hello</h1>`,
+				"<footer>4 words, 1 min read</footer>",
+			}
+			for _, exp := range expected {
+				if !strings.Contains(string(result), exp) {
+					t.Errorf("Expected %q in %q", exp, result)
+				}
+			}
+		})
+	}
+
+	expNames := []string{"dark", "github", "plain", "print"}
+	if strings.Join(layoutNames(), ",") != strings.Join(expNames, ",") {
+		t.Errorf("Expected layouts %v, got %v", expNames, layoutNames())
+	}
+}
+
+func TestLayoutErrors(t *testing.T) {
+	for _, name := range []string{"unknown", "base"} {
+		_, err := parseContent([]byte("# Hello\n"), "hello.md", config{layout: name})
+		if err == nil {
+			t.Fatalf("Expected error for layout %q, got nil", name)
+		}
+		if !strings.Contains(err.Error(), "unknown layout") {
+			t.Errorf("Unexpected error %q", err)
+		}
+	}
+}
+
+func TestLayoutBlocks(t *testing.T) {
+	// template made only of blocks overrides parts of the layout
+	tmpl := writeTemplate(t, `{{ define "header" }}<header>{{ template "nav" }}</header>{{ end }}
+{{ define "footer" }}<footer>by {{ .Meta.author }}, {{ date "Jan 2, 2006" .Meta.date }}</footer>{{ end }}`)
+	partial := writeTemplate(t, `{{ define "nav" }}<nav>home</nav>{{ end }}`)
+
+	input := []byte("---\nauthor: Jane\ndate: 2023-04-05\n---\n# Hello\n")
+
+	result, err := parseContent(input, "hello.md", config{layout: "plain", template: tmpl, partials: []string{partial}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"<title>Hello</title>",
+		"<header><nav>home</nav></header>",
+		"<footer>by Jane, Apr 5, 2023</footer>",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(string(result), exp) {
+			t.Errorf("Expected %q in %q", exp, result)
+		}
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	words := strings.Repeat("word ", 401)
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"WordCount", `{{ wordCount "<p>one <em>two</em></p>three" }}`, "3"},
+		{"ReadingTimeMin", `{{ readingTime "" }}`, "1"},
+		{"ReadingTime", `{{ readingTime "` + words + `" }}`, "3"},
+		{"DateString", `{{ date "02/01/2006" "2023-04-05" }}`, "05/04/2023"},
+		{"DateRFC3339", `{{ date "2006" "2023-04-05T10:00:00Z" }}`, "2023"},
+		{"DateMissing", `{{ date "2006" .Meta.date }}`, ""},
+		{"Now", `{{ date "2006" now }}`, time.Now().Format("2006")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := parseContent([]byte("text\n"), "funcs.md", config{template: writeTemplate(t, tc.input)})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(result) != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestTemplateFuncsInvalidDate(t *testing.T) {
+	_, err := parseContent([]byte("text\n"), "funcs.md", config{template: writeTemplate(t, `{{ date "2006" "yesterday" }}`)})
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), `invalid date "yesterday"`) {
+		t.Errorf("Unexpected error %q", err)
+	}
+}
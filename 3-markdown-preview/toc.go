@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+
+	"github.com/russross/blackfriday/v2"
+)
+
+// heading represents a document heading used to build the table of contents
+type heading struct {
+	level int
+	text  string
+	id    string
+}
+
+// uniqueHeadings collects headings of doc, renaming duplicated IDs as
+// id-1, id-2, ... so every heading gets its own stable anchor
+func uniqueHeadings(doc *blackfriday.Node) []heading {
+	headings := []heading{}
+	seen := map[string]int{}
+
+	doc.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		if !entering || node.Type != blackfriday.Heading || node.IsTitleblock {
+			return blackfriday.GoToNext
+		}
+
+		id := uniqueID(node.HeadingID, seen)
+		node.HeadingID = id
+
+		headings = append(headings, heading{
+			level: node.Level,
+			text:  nodeText(node),
+			id:    id,
+		})
+		return blackfriday.SkipChildren
+	})
+
+	return headings
+}
+
+// uniqueID returns id, or id-1, id-2, ... when it is already in seen. The
+// suffix grows until the ID is unused, as a heading may be named like a
+// renamed duplicate. The returned ID is recorded in seen
+func uniqueID(id string, seen map[string]int) string {
+	unique := id
+	for count := seen[id]; ; {
+		if _, ok := seen[unique]; !ok {
+			break
+		}
+		count++
+		seen[id] = count
+		unique = fmt.Sprintf("%s-%d", id, count)
+	}
+	seen[unique] = 0
+	return unique
+}
+
+// nodeText returns the plain text of node and its children
+func nodeText(node *blackfriday.Node) string {
+	var b strings.Builder
+
+	node.Walk(func(n *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		if entering && (n.Type == blackfriday.Text || n.Type == blackfriday.Code) {
+			b.Write(n.Literal)
+		}
+		return blackfriday.GoToNext
+	})
+	return b.String()
+}
+
+// buildTOC renders headings as nested HTML lists following heading levels
+func buildTOC(headings []heading) template.HTML {
+	if len(headings) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	// levels of the lists currently open
+	levels := []int{}
+
+	for i, h := range headings {
+		switch {
+		case i == 0 || h.level > levels[len(levels)-1]:
+			b.WriteString("<ul>\n")
+			levels = append(levels, h.level)
+		default:
+			b.WriteString("</li>\n")
+			// close deeper lists until we are back at this heading level
+			for len(levels) > 1 && h.level < levels[len(levels)-1] && h.level <= levels[len(levels)-2] {
+				b.WriteString("</ul>\n</li>\n")
+				levels = levels[:len(levels)-1]
+			}
+		}
+
+		fmt.Fprintf(&b, `<li><a href="#%s">%s</a>`, html.EscapeString(h.id), html.EscapeString(h.text))
+	}
+
+	b.WriteString("</li>\n")
+	for len(levels) > 1 {
+		b.WriteString("</ul>\n</li>\n")
+		levels = levels[:len(levels)-1]
+	}
+	b.WriteString("</ul>\n")
+
+	return template.HTML(`<nav class="toc">` + "\n" + b.String() + "</nav>\n")
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const tocInput = `# A
+
+## B
+
+### C
+
+## D
+
+# E
+
+## B
+`
+
+func TestBuildTOC(t *testing.T) {
+	result, err := parseContent([]byte(tocInput), "toc.md", config{template: writeTemplate(t, "{{ .TOC }}")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<nav class="toc">
+<ul>
+<li><a href="#a">A</a><ul>
+<li><a href="#b">B</a><ul>
+<li><a href="#c">C</a></li>
+</ul>
+</li>
+<li><a href="#d">D</a></li>
+</ul>
+</li>
+<li><a href="#e">E</a><ul>
+<li><a href="#b-1">B</a></li>
+</ul>
+</li>
+</ul>
+</nav>
+`
+	if string(result) != expected {
+		t.Errorf("Got:\n%s\nWant:\n%s", result, expected)
+	}
+}
+
+func TestHeadingIDs(t *testing.T) {
+	result, err := parseContent([]byte(tocInput), "toc.md", config{template: writeTemplate(t, "{{ .Body }}")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// duplicated heading gets its own anchor
+	for _, exp := range []string{`<h2 id="b">B</h2>`, `<h2 id="b-1">B</h2>`, `<h3 id="c">C</h3>`} {
+		if !strings.Contains(string(result), exp) {
+			t.Errorf("Expected %q in %q", exp, result)
+		}
+	}
+}
+
+func TestHeadingIDsCollision(t *testing.T) {
+	// renamed duplicate of A would clash with the A-1 heading
+	result, err := parseContent([]byte("# A-1\n\n# A\n\n# A\n"), "toc.md", config{template: writeTemplate(t, "{{ .TOC }}{{ .Body }}")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"a-1", "a", "a-2"} {
+		link := `<a href="#` + id + `">`
+		if !strings.Contains(string(result), link) {
+			t.Errorf("Expected %q in %q", link, result)
+		}
+		h := `<h1 id="` + id + `">`
+		if !strings.Contains(string(result), h) {
+			t.Errorf("Expected %q in %q", h, result)
+		}
+	}
+}
+
+func TestTOCInject(t *testing.T) {
+	testCases := []struct {
+		name      string
+		template  string
+		toc       bool
+		expPrefix string
+	}{
+		{"InjectTOC", "{{ .Body }}", true, `<nav class="toc">`},
+		{"NoTOCFlag", "{{ .Body }}", false, `<h1 id="a">A</h1>`},
+		{"TemplateHasTOC", "<main>{{ .Body }}</main>{{ .TOC }}", true, `<main><h1 id="a">A</h1>`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := config{
+				template: writeTemplate(t, tc.template),
+				toc:      tc.toc,
+			}
+			result, err := parseContent([]byte(tocInput), "toc.md", cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(string(result), tc.expPrefix) {
+				t.Errorf("Expected prefix %q, got %q", tc.expPrefix, result)
+			}
+			if strings.Count(string(result), `<nav class="toc">`) > 1 {
+				t.Errorf("TOC added more than once: %q", result)
+			}
+		})
+	}
+}
+
+// writeTemplate - saves template text to a temporary file
+func writeTemplate(t *testing.T, text string) string {
+	t.Helper()
+
+	tFname := filepath.Join(t.TempDir(), "test.html.tpl")
+	if err := os.WriteFile(tFname, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return tFname
+}
@@ -9,18 +9,6 @@ import (
 	"path/filepath"
 )
 
-// filterOut is responsbile for checking if path ...
-func filterOut(path string, ext string, minSize int64, info os.FileInfo) bool {
-	if info.IsDir() || info.Size() < minSize {
-		return true
-	}
-
-	if ext != "" && filepath.Ext(path) != ext {
-		return true
-	}
-	return false
-}
-
 // listFile is used to print output of path
 func listFile(path string, out io.Writer) error {
 	_, err := fmt.Fprintln(out, path)
@@ -1,36 +0,0 @@
-package main
-
-import (
-	"os"
-	"testing"
-)
-
-func TestFilterOut(t *testing.T) {
-	testCases := []struct {
-		name     string // testCase name
-		file     string
-		ext      string
-		minSize  int64
-		expected bool
-	}{
-		{"FilterNoExtension", "testdata/dir.log", "", 0, false},
-		{"FilterExtensionMatch", "testdata/dir.log", ".log", 0, false},
-		{"FilterExtensionNoMatch", "testdata/dir.log", ".sh", 0, true},
-		{"FilterExtensionSizeMatch", "testdata/dir.log", ".log", 10, false},
-		{"FilterExtensionSizeNoMatch", "testdata/dir.log", ".log", 20, true},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			info, err := os.Stat(tc.file)
-			if err != nil {
-				t.Fatal(err)
-			}
-			got := filterOut(tc.file, tc.ext, tc.minSize, info)
-			want := tc.expected
-			if got != want {
-				t.Errorf("got '%t', want '%t'\n", got, want)
-			}
-		})
-	}
-}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// filter checks if a file is kept, false filters it out
+type filter func(path string, info os.FileInfo) bool
+
+// filterChain keeps files matching every filter in it
+type filterChain []filter
+
+// match checks if path passes all filters of the chain
+func (c filterChain) match(path string, info os.FileInfo) bool {
+	for _, f := range c {
+		if !f(path, info) {
+			return false
+		}
+	}
+	return true
+}
+
+// newFilterChain builds the filters set in cfg, relative times are from now
+func newFilterChain(cfg config, now time.Time) (filterChain, error) {
+	c := filterChain{}
+
+	if exts := splitList(cfg.ext); len(exts) > 0 {
+		c = append(c, extFilter(exts))
+	}
+
+	if cfg.size > 0 || cfg.maxSize > 0 {
+		c = append(c, sizeFilter(cfg.size, cfg.maxSize))
+	}
+
+	if include := splitList(cfg.include); len(include) > 0 {
+		if err := checkPatterns(include); err != nil {
+			return nil, err
+		}
+		c = append(c, globFilter(include, true))
+	}
+
+	if exclude := splitList(cfg.exclude); len(exclude) > 0 {
+		if err := checkPatterns(exclude); err != nil {
+			return nil, err
+		}
+		c = append(c, globFilter(exclude, false))
+	}
+
+	if cfg.name != "" {
+		re, err := regexp.Compile(cfg.name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid name expression: %w", err)
+		}
+		c = append(c, nameFilter(re))
+	}
+
+	if cfg.after != "" || cfg.before != "" {
+		after, err := parseTime(cfg.after, now)
+		if err != nil {
+			return nil, err
+		}
+		before, err := parseTime(cfg.before, now)
+		if err != nil {
+			return nil, err
+		}
+		c = append(c, modifiedFilter(after, before))
+	}
+
+	if cfg.owner != "" {
+		f, err := ownerFilter(cfg.owner)
+		if err != nil {
+			return nil, err
+		}
+		c = append(c, f)
+	}
+
+	if cfg.perm != "" {
+		f, err := permFilter(cfg.perm)
+		if err != nil {
+			return nil, err
+		}
+		c = append(c, f)
+	}
+
+	return c, nil
+}
+
+// extFilter keeps files with any of the extensions
+func extFilter(exts []string) filter {
+	return func(path string, info os.FileInfo) bool {
+		for _, ext := range exts {
+			if filepath.Ext(path) == ext {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// sizeFilter keeps files of at least min bytes, and at most max if set
+func sizeFilter(min, max int64) filter {
+	return func(path string, info os.FileInfo) bool {
+		return info.Size() >= min && (max <= 0 || info.Size() <= max)
+	}
+}
+
+// globFilter keeps files matching any pattern when include is set, or
+// matching none of them otherwise. Patterns match the file name or path
+func globFilter(patterns []string, include bool) filter {
+	return func(path string, info os.FileInfo) bool {
+		for _, p := range patterns {
+			if matchName(p, filepath.Base(path)) || matchName(p, path) {
+				return include
+			}
+		}
+		return !include
+	}
+}
+
+// matchName reports if name matches pattern, patterns are checked upfront
+func matchName(pattern, name string) bool {
+	ok, _ := filepath.Match(pattern, name)
+	return ok
+}
+
+// checkPatterns validates glob patterns
+func checkPatterns(patterns []string) error {
+	for _, p := range patterns {
+		if _, err := filepath.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// nameFilter keeps files whose name matches re
+func nameFilter(re *regexp.Regexp) filter {
+	return func(path string, info os.FileInfo) bool {
+		return re.MatchString(filepath.Base(path))
+	}
+}
+
+// modifiedFilter keeps files modified after and before the given times,
+// a zero time isn't checked
+func modifiedFilter(after, before time.Time) filter {
+	return func(path string, info os.FileInfo) bool {
+		mod := info.ModTime()
+		return (after.IsZero() || mod.After(after)) && (before.IsZero() || mod.Before(before))
+	}
+}
+
+// parseTime parses a date, as 2006-01-02 or RFC3339, or an age such as 48h
+// meaning that long before now. Empty value returns a zero time
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if age, err := time.ParseDuration(value); err == nil {
+		return now.Add(-age), nil
+	}
+
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use a date like 2006-01-02 or an age like 48h", value)
+}
+
+// permFilter keeps files by permission bits, like find: "644" must match
+// exactly, "-644" needs all of these bits set, "/111" any of them
+func permFilter(value string) (filter, error) {
+	mode, digits := byte('='), value
+	if strings.HasPrefix(value, "-") || strings.HasPrefix(value, "/") {
+		mode, digits = value[0], value[1:]
+	}
+
+	bits, err := strconv.ParseUint(digits, 8, 32)
+	if err != nil || bits > 0777 {
+		return nil, fmt.Errorf("invalid permission %q: use octal bits like 644, -644 or /111", value)
+	}
+	perm := os.FileMode(bits)
+
+	return func(path string, info os.FileInfo) bool {
+		p := info.Mode().Perm()
+		switch mode {
+		case '-':
+			return p&perm == perm
+		case '/':
+			return p&perm != 0
+		}
+		return p == perm
+	}, nil
+}
+
+// splitList splits a comma separated flag value, skipping empty items
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
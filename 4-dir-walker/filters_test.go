@@ -0,0 +1,178 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createFilterFile creates a file for filter tests with given size, mode and modification time
+func createFilterFile(t *testing.T, name string, size int, perm os.FileMode, mod time.Time) (string, os.FileInfo) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, perm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path, info
+}
+
+func TestFilterChain(t *testing.T) {
+	now := time.Date(2023, 6, 15, 12, 0, 0, 0, time.Local)
+	path, info := createFilterFile(t, "app-12.log", 100, 0640, now.AddDate(0, 0, -3))
+
+	testCases := []struct {
+		name     string
+		cfg      config
+		expected bool
+	}{
+		{"NoFilters", config{}, true},
+		{"ExtensionsMatch", config{ext: ".txt,.log"}, true},
+		{"ExtensionsNoMatch", config{ext: ".txt, .sh"}, false},
+		{"SizeRangeMatch", config{size: 50, maxSize: 100}, true},
+		{"SizeTooSmall", config{size: 101}, false},
+		{"SizeTooBig", config{maxSize: 99}, false},
+		{"IncludeMatch", config{include: "*.txt,app-*"}, true},
+		{"IncludeNoMatch", config{include: "*.txt"}, false},
+		{"IncludePath", config{include: filepath.Join(filepath.Dir(path), "*.log")}, true},
+		{"ExcludeMatch", config{exclude: "*.log"}, false},
+		{"ExcludeNoMatch", config{exclude: "*.txt"}, true},
+		{"IncludeAndExclude", config{include: "*.log", exclude: "app-1?.log"}, false},
+		{"NameMatch", config{name: `^app-\d+\.`}, true},
+		{"NameNoMatch", config{name: `^app-\d\.`}, false},
+		{"ModifiedAfterAge", config{after: "96h"}, true},
+		{"ModifiedAfterAgeNoMatch", config{after: "48h"}, false},
+		{"ModifiedBeforeDate", config{before: "2023-06-13"}, true},
+		{"ModifiedBetween", config{after: "2023-06-01", before: "2023-06-12"}, false},
+		{"PermExact", config{perm: "640"}, true},
+		{"PermExactNoMatch", config{perm: "644"}, false},
+		{"PermAll", config{perm: "-600"}, true},
+		{"PermAllNoMatch", config{perm: "-604"}, false},
+		{"PermAny", config{perm: "/044"}, true},
+		{"PermAnyNoMatch", config{perm: "/111"}, false},
+		{"Combined", config{ext: ".log", size: 10, name: "^app", after: "2023-06-01"}, true},
+		{"CombinedNoMatch", config{ext: ".log", size: 10, name: "^web"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := newFilterChain(tc.cfg, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := c.match(path, info); got != tc.expected {
+				t.Errorf("got '%t', want '%t'", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestFilterChainExtSize(t *testing.T) {
+	testCases := []struct {
+		name     string // testCase name
+		file     string
+		ext      string
+		minSize  int64
+		expected bool
+	}{
+		{"FilterNoExtension", "testdata/dir.log", "", 0, true},
+		{"FilterExtensionMatch", "testdata/dir.log", ".log", 0, true},
+		{"FilterExtensionNoMatch", "testdata/dir.log", ".sh", 0, false},
+		{"FilterExtensionSizeMatch", "testdata/dir.log", ".log", 10, true},
+		{"FilterExtensionSizeNoMatch", "testdata/dir.log", ".log", 20, false},
+		{"FilterMultipleExtensionsMatch", "testdata/dir.log", ".sh,.log", 0, true},
+		{"FilterMultipleExtensionsNoMatch", "testdata/dir.log", ".sh,.gz", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			info, err := os.Stat(tc.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c, err := newFilterChain(config{ext: tc.ext, size: tc.minSize}, time.Now())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := c.match(tc.file, info); got != tc.expected {
+				t.Errorf("got '%t', want '%t'", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestFilterChainErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		cfg    config
+		expErr string
+	}{
+		{"InvalidPattern", config{include: "[a-"}, "invalid pattern"},
+		{"InvalidExcludePattern", config{exclude: "*.log,[z"}, "invalid pattern"},
+		{"InvalidName", config{name: "(app"}, "invalid name expression"},
+		{"InvalidTime", config{after: "last week"}, "invalid time"},
+		{"InvalidPerm", config{perm: "rwx"}, "invalid permission"},
+		{"PermOutOfRange", config{perm: "1777"}, "invalid permission"},
+		{"UnknownOwner", config{owner: "no-such-user-walk"}, "owner"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := newFilterChain(tc.cfg, time.Now())
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tc.expErr) {
+				t.Errorf("Expected error containing %q, got %q", tc.expErr, err)
+			}
+		})
+	}
+}
+
+func TestOwnerFilter(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("owner filter not supported on Windows")
+	}
+
+	path, info := createFilterFile(t, "owned.txt", 1, 0644, time.Now())
+	uid := os.Getuid()
+
+	testCases := []struct {
+		name     string
+		owner    string
+		expected bool
+	}{
+		{"CurrentUser", strconv.Itoa(uid), true},
+		{"OtherUser", strconv.Itoa(uid + 1), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := ownerFilter(tc.owner)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := f(path, info); got != tc.expected {
+				t.Errorf("got '%t', want '%t'", got, tc.expected)
+			}
+		})
+	}
+}
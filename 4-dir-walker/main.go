@@ -6,17 +6,37 @@ import (
 	"io"
 	"log"
 	"os"
-	"path/filepath"
+	"time"
 )
 
 // config type represents the config used for walking directories
 type config struct {
-	// extension to filter out
+	// comma separated extensions to filter out
 	ext string
 
 	// minimum file size
 	size int64
 
+	// maximum file size, no limit when 0
+	maxSize int64
+
+	// comma separated glob patterns files must match, or must not match
+	include string
+	exclude string
+
+	// regular expression file names must match
+	name string
+
+	// modified after and before, as date or age such as 48h
+	after  string
+	before string
+
+	// user name or ID owning files
+	owner string
+
+	// permission bits, exact or find-like -bits (all) and /bits (any)
+	perm string
+
 	// list files
 	list bool
 
@@ -28,16 +48,33 @@ type config struct {
 
 	// archive directory
 	archive string
+
+	// report what would be archived or deleted without doing it
+	dryRun bool
+
+	// ask before archiving or deleting, answer is read from in
+	confirm bool
+	in      io.Reader
 }
 
 func main() {
 	root := flag.String("root", ".", "root directory to start")
 	list := flag.Bool("list", false, "list files only")
 	size := flag.Int64("size", 0, "minimum file size")
-	ext := flag.String("ext", "", "file extension to filter out")
+	maxSize := flag.Int64("max-size", 0, "maximum file size, 0 for no limit")
+	ext := flag.String("ext", "", "comma separated file extensions to filter out")
+	include := flag.String("include", "", "comma separated glob patterns of file names or paths to include")
+	exclude := flag.String("exclude", "", "comma separated glob patterns of file names or paths to exclude")
+	name := flag.String("name", "", "regular expression file names must match")
+	after := flag.String("modified-after", "", "only files modified after a date (2006-01-02) or an age (48h)")
+	before := flag.String("modified-before", "", "only files modified before a date (2006-01-02) or an age (48h)")
+	owner := flag.String("owner", "", "only files owned by this user name or ID")
+	perm := flag.String("perm", "", "only files with permissions: 644 exactly, -644 all bits set, /111 any bit set")
 	del := flag.Bool("del", false, "delete matching files")
 	logFile := flag.String("log", "", "log delete operations to this file")
 	archive := flag.String("archive", "", "archive directory")
+	dryRun := flag.Bool("dry-run", false, "show what would be archived or deleted without changing anything")
+	confirmFlag := flag.Bool("confirm", false, "show what will be archived or deleted and ask before doing it")
 	flag.Parse()
 
 	var (
@@ -57,10 +94,21 @@ func main() {
 	c := config{
 		ext:     *ext,
 		size:    *size,
+		maxSize: *maxSize,
+		include: *include,
+		exclude: *exclude,
+		name:    *name,
+		after:   *after,
+		before:  *before,
+		owner:   *owner,
+		perm:    *perm,
 		list:    *list,
 		del:     *del,
 		wLog:    f,
 		archive: *archive,
+		dryRun:  *dryRun,
+		confirm: *confirmFlag,
+		in:      os.Stdin,
 	}
 
 	if err := run(*root, os.Stdout, c); err != nil {
@@ -70,33 +118,68 @@ func main() {
 }
 
 func run(rootDir string, out io.Writer, cfg config) error {
-	delLogger := log.New(cfg.wLog, "DELETED FILE: ", log.LstdFlags)
-	return filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
+	filters, err := newFilterChain(cfg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// decide on every file first, so nothing changes until the plan is known
+	files, err := planFiles(rootDir, filters)
+	if err != nil {
+		return err
+	}
+
+	action := cfg.action()
+
+	// listing is safe, dry run and confirmation only apply to changes
+	if action != "" && (cfg.dryRun || cfg.confirm) {
+		if err := printPlan(out, files, action); err != nil {
 			return err
 		}
-		if filterOut(path, cfg.ext, cfg.size, info) {
+
+		if cfg.dryRun {
 			return nil
 		}
 
+		ok, err := confirm(cfg.in, out)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			_, err := fmt.Fprintln(out, "Aborted, no files changed")
+			return err
+		}
+	}
+
+	delLogger := log.New(cfg.wLog, "DELETED FILE: ", log.LstdFlags)
+	for _, f := range files {
 		// if list was explicilty set, don't do anything else
 		if cfg.list {
-			return listFile(path, out)
+			if err := listFile(f.path, out); err != nil {
+				return err
+			}
+			continue
 		}
 
 		// Archive files before making a deletion operation
 		if cfg.archive != "" {
-			if err := archiveFile(cfg.archive, rootDir, path); err != nil {
+			if err := archiveFile(cfg.archive, rootDir, f.path); err != nil {
 				return err
 			}
 		}
 		// if delete flag is passed
 		if cfg.del {
-			return delFile(path, delLogger)
+			if err := delFile(f.path, delLogger); err != nil {
+				return err
+			}
+			continue
 		}
 
 		// List is the default option if nothing else was set
-		return listFile(path, out)
-	})
+		if err := listFile(f.path, out); err != nil {
+			return err
+		}
+	}
 
+	return nil
 }
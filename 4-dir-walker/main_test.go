@@ -90,6 +90,34 @@ func TestRun(t *testing.T) {
 			},
 			expected: "",
 		},
+		{
+			name: "FilterMultipleExtensions",
+			root: "testdata",
+			cfg: config{
+				ext:  ".log,.sh",
+				list: true,
+			},
+			expected: "testdata/dir.log\ntestdata/dir2/script.sh\n",
+		},
+		{
+			name: "FilterExcludePath",
+			root: "testdata",
+			cfg: config{
+				exclude: "testdata/dir2/*",
+				list:    true,
+			},
+			expected: "testdata/dir.log\n",
+		},
+		{
+			name: "FilterIncludeMaxSize",
+			root: "testdata",
+			cfg: config{
+				include: "*.log,*.sh",
+				maxSize: 1,
+				list:    true,
+			},
+			expected: "testdata/dir2/script.sh\n",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -111,6 +139,18 @@ func TestRun(t *testing.T) {
 
 }
 
+func TestRunInvalidFilter(t *testing.T) {
+	var buffer bytes.Buffer
+
+	err := run("testdata", &buffer, config{name: "[", list: true})
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	if buffer.Len() != 0 {
+		t.Errorf("Expected no output, got %q", buffer.String())
+	}
+}
+
 func TestRunDeleteExtension(t *testing.T) {
 	testCases := []struct {
 		name        string
@@ -282,3 +322,126 @@ func TestRunArchive(t *testing.T) {
 		})
 	}
 }
+
+func TestRunDryRun(t *testing.T) {
+	testCases := []struct {
+		name     string
+		cfg      config
+		expected string
+	}{
+		{
+			name:     "Delete",
+			cfg:      config{ext: ".log", del: true},
+			expected: "would delete: %[1]s/file1.log\nwould delete: %[1]s/file2.log\nPlan: delete 2 file(s), 10 bytes reclaimed\n",
+		},
+		{
+			name:     "ArchiveAndDelete",
+			cfg:      config{ext: ".log", del: true, archive: "archive"},
+			expected: "would archive and delete: %[1]s/file1.log\nwould archive and delete: %[1]s/file2.log\nPlan: archive and delete 2 file(s), 10 bytes reclaimed\n",
+		},
+		{
+			name:     "NoMatch",
+			cfg:      config{ext: ".txt", del: true},
+			expected: "Plan: delete 0 file(s), 0 bytes reclaimed\n",
+		},
+		{
+			// listing changes nothing, so it is done as usual
+			name:     "List",
+			cfg:      config{ext: ".log", list: true},
+			expected: "%[1]s/file1.log\n%[1]s/file2.log\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buffer, logBuffer bytes.Buffer
+
+			tempDir, cleanup := createTempDir(t, map[string]int{".log": 2, ".gz": 1})
+			defer cleanup()
+
+			tc.cfg.dryRun = true
+			tc.cfg.wLog = &logBuffer
+			if tc.cfg.archive != "" {
+				tc.cfg.archive = t.TempDir()
+			}
+
+			if err := run(tempDir, &buffer, tc.cfg); err != nil {
+				t.Fatal(err)
+			}
+
+			want := tc.expected
+			if strings.Contains(want, "%[1]s") {
+				want = fmt.Sprintf(tc.expected, tempDir)
+			}
+			if got := buffer.String(); got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+
+			// nothing was changed
+			filesLeft, err := os.ReadDir(tempDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(filesLeft) != 3 {
+				t.Errorf("Expected 3 files left, got %d instead", len(filesLeft))
+			}
+			if logBuffer.Len() != 0 {
+				t.Errorf("Expected no deletions logged, got %q", logBuffer.String())
+			}
+			if tc.cfg.archive != "" {
+				if archived, _ := os.ReadDir(tc.cfg.archive); len(archived) != 0 {
+					t.Errorf("Expected nothing archived, got %d files", len(archived))
+				}
+			}
+		})
+	}
+}
+
+func TestRunConfirm(t *testing.T) {
+	testCases := []struct {
+		name      string
+		answer    string
+		nLeft     int
+		expSuffix string
+	}{
+		{"Yes", "y\n", 1, "Proceed? [y/N]: "},
+		{"YesWord", " YES \n", 1, "Proceed? [y/N]: "},
+		{"No", "n\n", 3, "Proceed? [y/N]: Aborted, no files changed\n"},
+		{"Empty", "\n", 3, "Proceed? [y/N]: Aborted, no files changed\n"},
+		{"EOF", "", 3, "Proceed? [y/N]: Aborted, no files changed\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buffer, logBuffer bytes.Buffer
+
+			tempDir, cleanup := createTempDir(t, map[string]int{".log": 2, ".gz": 1})
+			defer cleanup()
+
+			cfg := config{
+				ext:     ".log",
+				del:     true,
+				confirm: true,
+				in:      strings.NewReader(tc.answer),
+				wLog:    &logBuffer,
+			}
+
+			if err := run(tempDir, &buffer, cfg); err != nil {
+				t.Fatal(err)
+			}
+
+			got := buffer.String()
+			if !strings.HasPrefix(got, "would delete: ") || !strings.HasSuffix(got, "Plan: delete 2 file(s), 10 bytes reclaimed\n"+tc.expSuffix) {
+				t.Errorf("Unexpected output %q", got)
+			}
+
+			filesLeft, err := os.ReadDir(tempDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(filesLeft) != tc.nLeft {
+				t.Errorf("Expected %d files left, got %d instead", tc.nLeft, len(filesLeft))
+			}
+		})
+	}
+}
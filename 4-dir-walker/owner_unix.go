@@ -0,0 +1,30 @@
+//go:build !windows
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// ownerFilter keeps files owned by a user name or numeric user ID
+func ownerFilter(owner string) (filter, error) {
+	uid, err := strconv.ParseUint(owner, 10, 32)
+	if err != nil {
+		u, err := user.Lookup(owner)
+		if err != nil {
+			return nil, fmt.Errorf("invalid owner: %w", err)
+		}
+		if uid, err = strconv.ParseUint(u.Uid, 10, 32); err != nil {
+			return nil, fmt.Errorf("invalid owner %q: %w", owner, err)
+		}
+	}
+
+	return func(path string, info os.FileInfo) bool {
+		stat, ok := info.Sys().(*syscall.Stat_t)
+		return ok && uint64(stat.Uid) == uid
+	}, nil
+}
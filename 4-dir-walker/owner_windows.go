@@ -0,0 +1,10 @@
+//go:build windows
+
+package main
+
+import "fmt"
+
+// ownerFilter isn't supported, files have no numeric owner on Windows
+func ownerFilter(owner string) (filter, error) {
+	return nil, fmt.Errorf("owner filter not supported on Windows")
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// plannedFile is a file selected by the filters, to act on once walking is done
+type plannedFile struct {
+	path string
+	size int64
+}
+
+// planFiles walks rootDir collecting files that pass filters, without changing them
+func planFiles(rootDir string, filters filterChain) ([]plannedFile, error) {
+	files := []plannedFile{}
+
+	err := filepath.Walk(rootDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !filters.match(path, info) {
+			return nil
+		}
+
+		files = append(files, plannedFile{path: path, size: info.Size()})
+		return nil
+	})
+
+	return files, err
+}
+
+// action describes the changes run makes to files, empty when only listing
+func (cfg config) action() string {
+	switch {
+	case cfg.list:
+		return ""
+	case cfg.archive != "" && cfg.del:
+		return "archive and delete"
+	case cfg.del:
+		return "delete"
+	case cfg.archive != "":
+		return "archive"
+	}
+	return ""
+}
+
+// printPlan reports what action would do to each file, with a summary of
+// file count and total size, reclaimed when files are deleted
+func printPlan(out io.Writer, files []plannedFile, action string) error {
+	var total int64
+	for _, f := range files {
+		total += f.size
+		if _, err := fmt.Fprintf(out, "would %s: %s\n", action, f.path); err != nil {
+			return err
+		}
+	}
+
+	reclaimed := ""
+	if strings.HasSuffix(action, "delete") {
+		reclaimed = " reclaimed"
+	}
+
+	_, err := fmt.Fprintf(out, "Plan: %s %d file(s), %d bytes%s\n", action, len(files), total, reclaimed)
+	return err
+}
+
+// confirm asks to proceed, only an answer of y or yes confirms
+func confirm(in io.Reader, out io.Writer) (bool, error) {
+	if in == nil {
+		return false, errors.New("confirmation needs an input to read the answer from")
+	}
+
+	if _, err := fmt.Fprint(out, "Proceed? [y/N]: "); err != nil {
+		return false, err
+	}
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
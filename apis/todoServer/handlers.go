@@ -131,13 +131,20 @@ func patchHandler(w http.ResponseWriter, r *http.Request,
 
 	q := r.URL.Query()
 
-	if _, ok := q["complete"]; !ok {
-		message := "Missing query param 'complete'"
+	_, complete := q["complete"]
+	_, uncomplete := q["uncomplete"]
+
+	switch {
+	case complete:
+		list.Complete(id)
+	case uncomplete:
+		list.Uncomplete(id)
+	default:
+		message := "Missing query param 'complete' or 'uncomplete'"
 		replyError(w, r, http.StatusBadRequest, message)
 		return
 	}
 
-	list.Complete(id)
 	if err := list.Save(todoFile); err != nil {
 		replyError(w, r, http.StatusInternalServerError, err.Error())
 		return
@@ -145,3 +145,54 @@ func TestGet(t *testing.T) {
 		})
 	}
 }
+
+func TestPatch(t *testing.T) {
+	testCases := []struct {
+		name    string
+		query   string
+		expCode int
+		expDone bool
+	}{
+		{"Complete", "?complete", http.StatusNoContent, true},
+		{"Uncomplete", "?uncomplete", http.StatusNoContent, false},
+		{"MissingParam", "", http.StatusBadRequest, false},
+	}
+
+	url, cleanup := setupAPI(t)
+	defer cleanup()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPatch, url+"/todo/1"+tc.query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.Body.Close()
+
+			if r.StatusCode != tc.expCode {
+				t.Fatalf("Expected %q, got %q", http.StatusText(tc.expCode), http.StatusText(r.StatusCode))
+			}
+
+			var resp struct {
+				Results todo.List `json:"results"`
+			}
+			r, err = http.Get(url + "/todo/1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Body.Close()
+
+			if err := json.NewDecoder(r.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Results[0].Done != tc.expDone {
+				t.Errorf("Expected item done to be %t, got %t", tc.expDone, resp.Results[0].Done)
+			}
+		})
+	}
+}